@@ -1,15 +1,25 @@
 package twizo
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 )
 
 // ApplicationVerifyCredentialsRequest empty struct placeholder (future use)
-type ApplicationVerifyCredentialsRequest struct{}
+type ApplicationVerifyCredentialsRequest struct {
+	client *Client
+}
 
 // Submit wil submit the balance request
 func (request *ApplicationVerifyCredentialsRequest) Submit() (*ApplicationVerifyCredentialsResponse, error) {
+	return request.SubmitContext(context.Background())
+}
+
+// SubmitContext verifies the credentials using ctx for the call
+func (request *ApplicationVerifyCredentialsRequest) SubmitContext(
+	ctx context.Context,
+) (*ApplicationVerifyCredentialsResponse, error) {
 	response := &ApplicationVerifyCredentialsResponse{}
 	response.isKeyValid = true
 
@@ -18,7 +28,9 @@ func (request *ApplicationVerifyCredentialsRequest) Submit() (*ApplicationVerify
 		return nil, err
 	}
 
-	err = GetClient(RegionCurrent, APIKey).Call(
+	err = clientOrDefault(request.client).call(
+		ctx,
+		EndpointApplicationVerifyCredentials,
 		http.MethodGet,
 		apiURL,
 		nil,
@@ -26,7 +38,7 @@ func (request *ApplicationVerifyCredentialsRequest) Submit() (*ApplicationVerify
 		response,
 	)
 
-	if apiError, ok := err.(*APIError); ok {
+	if apiError, ok := asAPIError(err); ok {
 		if apiError.NotAuthorized() {
 			response.isKeyValid = false
 			err = nil
@@ -86,12 +98,22 @@ func (response ApplicationVerifyCredentialsResponse) IsKeyValid() bool {
 
 // NewApplicationVerifyCredentials creates a new ApplicationVerifyCredentials
 func NewApplicationVerifyCredentials() *ApplicationVerifyCredentialsRequest {
-	request := &ApplicationVerifyCredentialsRequest{}
+	return DefaultClient.NewApplicationVerifyCredentials()
+}
+
+// NewApplicationVerifyCredentials creates a new ApplicationVerifyCredentials that will use the client
+func (c *Client) NewApplicationVerifyCredentials() *ApplicationVerifyCredentialsRequest {
+	request := &ApplicationVerifyCredentialsRequest{client: c}
 	return request
 }
 
 // ApplicationVerifyCredentials retrieves the credit balance of the api key
 func ApplicationVerifyCredentials() (*ApplicationVerifyCredentialsResponse, error) {
-	request := NewApplicationVerifyCredentials()
+	return DefaultClient.ApplicationVerifyCredentials()
+}
+
+// ApplicationVerifyCredentials verifies the api key of the client
+func (c *Client) ApplicationVerifyCredentials() (*ApplicationVerifyCredentialsResponse, error) {
+	request := c.NewApplicationVerifyCredentials()
 	return request.Submit()
 }
@@ -1,6 +1,7 @@
 package twizo
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -15,6 +16,7 @@ type jsonBackupCodeRequest struct {
 // BackupCodeRequest request for creating backup codes for id
 type BackupCodeRequest struct {
 	identifier string
+	client     *Client
 }
 
 // MarshalJSON is used to convert NumberLookupRequest to json
@@ -28,12 +30,19 @@ func (request *BackupCodeRequest) MarshalJSON() ([]byte, error) {
 
 // Delete the existing (if any) backup codes for identifier
 func (request *BackupCodeRequest) Delete() error {
+	return request.DeleteContext(context.Background())
+}
+
+// DeleteContext deletes the backup codes of the identifier using ctx for the call
+func (request *BackupCodeRequest) DeleteContext(ctx context.Context) error {
 	apiURL, err := GetURLFor(fmt.Sprintf("backupcode/%s", url.PathEscape(request.GetIdentifier())))
 	if err != nil {
 		return err
 	}
 
-	err = GetClient(RegionCurrent, APIKey).Call(
+	err = clientOrDefault(request.client).call(
+		ctx,
+		EndpointBackupCodeDelete,
 		http.MethodDelete,
 		apiURL,
 		request,
@@ -49,13 +58,20 @@ func (request *BackupCodeRequest) Delete() error {
 
 // Status will retrieve the backupcode status
 func (request *BackupCodeRequest) Status() (*BackupCodeResponse, error) {
+	return request.StatusContext(context.Background())
+}
+
+// StatusContext retrieves the backupcode status using ctx for the call
+func (request *BackupCodeRequest) StatusContext(ctx context.Context) (*BackupCodeResponse, error) {
 	response := &BackupCodeResponse{}
 	apiURL, err := GetURLFor(fmt.Sprintf("backupcode/%s", url.PathEscape(request.GetIdentifier())))
 	if err != nil {
 		return nil, err
 	}
 
-	err = GetClient(RegionCurrent, APIKey).Call(
+	err = clientOrDefault(request.client).call(
+		ctx,
+		EndpointBackupCodeStatus,
 		http.MethodGet,
 		apiURL,
 		request,
@@ -68,6 +84,11 @@ func (request *BackupCodeRequest) Status() (*BackupCodeResponse, error) {
 
 // Verify token did belong to the identifier of the request
 func (request *BackupCodeRequest) Verify(token string) (*BackupCodeResponse, error) {
+	return request.VerifyContext(context.Background(), token)
+}
+
+// VerifyContext verifies the token using ctx for the call
+func (request *BackupCodeRequest) VerifyContext(ctx context.Context, token string) (*BackupCodeResponse, error) {
 	response := &BackupCodeResponse{}
 
 	apiURL, err := GetURLFor(fmt.Sprintf("backupcode/%s", url.PathEscape(request.GetIdentifier())))
@@ -79,7 +100,9 @@ func (request *BackupCodeRequest) Verify(token string) (*BackupCodeResponse, err
 	q.Set("token", token)
 	apiURL.RawQuery = q.Encode()
 
-	err = GetClient(RegionCurrent, APIKey).Call(
+	err = clientOrDefault(request.client).call(
+		ctx,
+		EndpointBackupCodeVerify,
 		http.MethodGet,
 		apiURL,
 		request,
@@ -87,31 +110,22 @@ func (request *BackupCodeRequest) Verify(token string) (*BackupCodeResponse, err
 		response,
 	)
 
-	if apiError, ok := err.(*APIError); ok {
+	if apiError, ok := asAPIError(err); ok && apiError.Status() == http.StatusNotFound {
+		// unknown identifier, the token can never be valid
 		response.verificationResponse = &VerificationResponse{}
-		if apiError.Status() == http.StatusNotFound {
-			response.verificationResponse.statusCode = VerificationTokenInvalid
-		} else if apiError.Status() == http.StatusUnprocessableEntity &&
-			VerificationStatusCode(apiError.ErrorCode()) == VerificationTokenInvalid {
-			response.verificationResponse.statusCode = VerificationTokenInvalid
-		} else if apiError.Status() == http.StatusLocked &&
-			VerificationStatusCode(apiError.ErrorCode()) == VerificationTokenExpired {
-			response.verificationResponse.statusCode = VerificationTokenExpired
-		} else if apiError.Status() == http.StatusLocked &&
-			VerificationStatusCode(apiError.ErrorCode()) == VerificationTokenAlreadyVerified {
-			response.verificationResponse.statusCode = VerificationTokenAlreadyVerified
-		} else if apiError.Status() == http.StatusLocked &&
-			VerificationStatusCode(apiError.ErrorCode()) == VerificationTokenFailed {
-			response.verificationResponse.statusCode = VerificationTokenFailed
-		} else {
-			// undocumented response, error out
-			return nil, err
-		}
+		response.verificationResponse.statusCode = VerificationTokenInvalid
 		return response, nil
 	}
 
 	if err != nil {
-		return nil, err
+		statusCode, ok := verificationStatusFromError(err)
+		if !ok {
+			// undocumented response, error out
+			return nil, err
+		}
+		response.verificationResponse = &VerificationResponse{}
+		response.verificationResponse.statusCode = statusCode
+		return response, nil
 	}
 
 	return response, nil
@@ -119,18 +133,29 @@ func (request *BackupCodeRequest) Verify(token string) (*BackupCodeResponse, err
 
 // Update the existing (or create new ones if they do not exist) backup tokens
 func (request *BackupCodeRequest) Update() (*BackupCodeResponse, error) {
-	return request.doUpdateCreate(true)
+	return request.UpdateContext(context.Background())
+}
+
+// UpdateContext updates the backup codes using ctx for the call
+func (request *BackupCodeRequest) UpdateContext(ctx context.Context) (*BackupCodeResponse, error) {
+	return request.doUpdateCreate(ctx, true)
 }
 
 // Create wil submit the balance request
 func (request *BackupCodeRequest) Create() (*BackupCodeResponse, error) {
-	return request.doUpdateCreate(false)
+	return request.CreateContext(context.Background())
 }
 
-func (request *BackupCodeRequest) doUpdateCreate(update bool) (*BackupCodeResponse, error) {
+// CreateContext creates the backup codes using ctx for the call
+func (request *BackupCodeRequest) CreateContext(ctx context.Context) (*BackupCodeResponse, error) {
+	return request.doUpdateCreate(ctx, false)
+}
+
+func (request *BackupCodeRequest) doUpdateCreate(ctx context.Context, update bool) (*BackupCodeResponse, error) {
 	response := &BackupCodeResponse{}
 
 	urlPart := "backupcode"
+	endpoint := EndpointBackupCodeCreate
 	method := http.MethodPost
 	expect := http.StatusCreated
 	if update {
@@ -138,6 +163,7 @@ func (request *BackupCodeRequest) doUpdateCreate(update bool) (*BackupCodeRespon
 			"backupcode/%s",
 			url.PathEscape(request.GetIdentifier()),
 		)
+		endpoint = EndpointBackupCodeUpdate
 		method = http.MethodPut
 		expect = http.StatusOK
 	}
@@ -147,7 +173,9 @@ func (request *BackupCodeRequest) doUpdateCreate(update bool) (*BackupCodeRespon
 		return nil, err
 	}
 
-	err = GetClient(RegionCurrent, APIKey).Call(
+	err = clientOrDefault(request.client).call(
+		ctx,
+		endpoint,
 		method,
 		apiURL,
 		request,
@@ -155,7 +183,7 @@ func (request *BackupCodeRequest) doUpdateCreate(update bool) (*BackupCodeRespon
 		response,
 	)
 
-	if apiError, ok := err.(*APIError); !update && ok && apiError.Conflict() {
+	if apiError, ok := asAPIError(err); !update && ok && apiError.Conflict() {
 		response.alreadyExists = true
 		response.identifier = request.GetIdentifier()
 		err = nil
@@ -260,44 +288,79 @@ func (response BackupCodeResponse) AlreadyExists() bool {
 
 // NewBackupCodeRequest creates a new BackupCodeRequest
 func NewBackupCodeRequest(id string) *BackupCodeRequest {
-	request := &BackupCodeRequest{identifier: id}
+	return DefaultClient.NewBackupCodeRequest(id)
+}
+
+// NewBackupCodeRequest creates a new BackupCodeRequest that will use the client
+func (c *Client) NewBackupCodeRequest(id string) *BackupCodeRequest {
+	request := &BackupCodeRequest{identifier: id, client: c}
 	return request
 }
 
 // BackupCodeCreate creates new backup codes for an identifier
 func BackupCodeCreate(id string) (*BackupCodeResponse, error) {
-	request := NewBackupCodeRequest(id)
+	return DefaultClient.BackupCodeCreate(id)
+}
+
+// BackupCodeCreate creates new backup codes for an identifier using the client
+func (c *Client) BackupCodeCreate(id string) (*BackupCodeResponse, error) {
+	request := c.NewBackupCodeRequest(id)
 	return request.Create()
 }
 
 // BackupCodeUpdate updates the backup codes for an identifier (this will
 // invalidate the old backup codes)
 func BackupCodeUpdate(id string) (*BackupCodeResponse, error) {
-	request := NewBackupCodeRequest(id)
+	return DefaultClient.BackupCodeUpdate(id)
+}
+
+// BackupCodeUpdate updates the backup codes for an identifier using the client
+func (c *Client) BackupCodeUpdate(id string) (*BackupCodeResponse, error) {
+	request := c.NewBackupCodeRequest(id)
 	return request.Update()
 }
 
 // BackupCodeDelete will delete the backup codes for the identifier supplied
 func BackupCodeDelete(id string) error {
-	request := NewBackupCodeRequest(id)
+	return DefaultClient.BackupCodeDelete(id)
+}
+
+// BackupCodeDelete will delete the backup codes for the identifier using the client
+func (c *Client) BackupCodeDelete(id string) error {
+	request := c.NewBackupCodeRequest(id)
 	return request.Delete()
 }
 
 // BackupCodeVerify will verify a token for an intentifier
 func BackupCodeVerify(id string, token string) (*BackupCodeResponse, error) {
-	request := NewBackupCodeRequest(id)
+	return DefaultClient.BackupCodeVerify(id, token)
+}
+
+// BackupCodeVerify will verify a token for an identifier using the client
+func (c *Client) BackupCodeVerify(id string, token string) (*BackupCodeResponse, error) {
+	request := c.NewBackupCodeRequest(id)
 	return request.Verify(token)
 }
 
 // BackupCodeStatus will return backup status
 func BackupCodeStatus(id string) (*BackupCodeResponse, error) {
-	request := NewBackupCodeRequest(id)
+	return DefaultClient.BackupCodeStatus(id)
+}
+
+// BackupCodeStatus will return backup status using the client
+func (c *Client) BackupCodeStatus(id string) (*BackupCodeResponse, error) {
+	request := c.NewBackupCodeRequest(id)
 	return request.Status()
 }
 
 // BackupCodeAmountLeft returns the amount of codes left or 0 on error
 func BackupCodeAmountLeft(id string) (int, error) {
-	response, err := BackupCodeStatus(id)
+	return DefaultClient.BackupCodeAmountLeft(id)
+}
+
+// BackupCodeAmountLeft returns the amount of codes left or 0 on error using the client
+func (c *Client) BackupCodeAmountLeft(id string) (int, error) {
+	response, err := c.BackupCodeStatus(id)
 	if err != nil {
 		return 0, err
 	}
@@ -346,3 +346,42 @@ func TestBackupCodeVerify(t *testing.T) {
 		return
 	}
 }
+
+func TestBackupCodeVerifyErrorStatus(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	verifyURL := fmt.Sprintf(
+		"https://%s/%s/backupcode/identifier?token=12345",
+		twizo.GetHostForRegion(twizo.RegionCurrent),
+		twizo.ClientAPIVersion,
+	)
+
+	tests := []struct {
+		status    int
+		errorCode int
+		expect    twizo.VerificationStatusCode
+	}{
+		{http.StatusNotFound, 0, twizo.VerificationTokenInvalid},
+		{http.StatusUnprocessableEntity, 103, twizo.VerificationTokenInvalid},
+		{http.StatusLocked, 104, twizo.VerificationTokenFailed},
+	}
+
+	for _, test := range tests {
+		httpmock.RegisterResponder(http.MethodGet, verifyURL, newProblemResponder(test.status, test.errorCode))
+
+		response, err := twizo.BackupCodeVerify("identifier", "12345")
+		if err != nil {
+			t.Fatalf("Expecting no error for status [%d:%d] got [%#v]", test.status, test.errorCode, err)
+		}
+		if response.GetVerificationResponse().GetStatusCode() != test.expect {
+			t.Fatalf(
+				"Invalid status code for [%d:%d] expecting [%d] got [%d]",
+				test.status,
+				test.errorCode,
+				test.expect,
+				response.GetVerificationResponse().GetStatusCode(),
+			)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package twizo
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 )
@@ -72,10 +73,17 @@ func (response BalanceGetResponse) GetFreeVerifications() int {
 }
 
 // BalanceGetRequest empty struct placeholder (future use)
-type BalanceGetRequest struct{}
+type BalanceGetRequest struct {
+	client *Client
+}
 
 // Submit wil submit the balance request
 func (request *BalanceGetRequest) Submit() (*BalanceGetResponse, error) {
+	return request.SubmitContext(context.Background())
+}
+
+// SubmitContext retrieves the balance using ctx for the call
+func (request *BalanceGetRequest) SubmitContext(ctx context.Context) (*BalanceGetResponse, error) {
 	response := &BalanceGetResponse{}
 
 	apiURL, err := GetURLFor("wallet/getbalance")
@@ -83,7 +91,9 @@ func (request *BalanceGetRequest) Submit() (*BalanceGetResponse, error) {
 		return nil, err
 	}
 
-	err = GetClient(RegionCurrent, APIKey).Call(
+	err = clientOrDefault(request.client).call(
+		ctx,
+		EndpointBalanceGet,
 		http.MethodGet,
 		apiURL,
 		nil,
@@ -99,12 +109,22 @@ func (request *BalanceGetRequest) Submit() (*BalanceGetResponse, error) {
 
 // NewBalanceGetRequest creates a new BalanceRequest
 func NewBalanceGetRequest() *BalanceGetRequest {
-	request := &BalanceGetRequest{}
+	return DefaultClient.NewBalanceGetRequest()
+}
+
+// NewBalanceGetRequest creates a new BalanceRequest that will use the client
+func (c *Client) NewBalanceGetRequest() *BalanceGetRequest {
+	request := &BalanceGetRequest{client: c}
 	return request
 }
 
 // BalanceGet retrieves the credit balance of the api key
 func BalanceGet() (*BalanceGetResponse, error) {
-	request := NewBalanceGetRequest()
+	return DefaultClient.BalanceGet()
+}
+
+// BalanceGet retrieves the credit balance of the api key of the client
+func (c *Client) BalanceGet() (*BalanceGetResponse, error) {
+	request := c.NewBalanceGetRequest()
 	return request.Submit()
 }
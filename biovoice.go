@@ -1,6 +1,7 @@
 package twizo
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -15,6 +16,7 @@ type jsonBioVoiceRequest struct {
 // BioVoiceRequest request for creating backup codes for id
 type BioVoiceRequest struct {
 	recipient Recipient
+	client    *Client
 }
 
 // MarshalJSON is used to convert BioVoiceRequest to json
@@ -28,6 +30,11 @@ func (request *BioVoiceRequest) MarshalJSON() ([]byte, error) {
 
 // DeleteSubscription the existing (if any) biovoice subscription
 func (request *BioVoiceRequest) DeleteSubscription() error {
+	return request.DeleteSubscriptionContext(context.Background())
+}
+
+// DeleteSubscriptionContext deletes the biovoice subscription using ctx for the call
+func (request *BioVoiceRequest) DeleteSubscriptionContext(ctx context.Context) error {
 	apiURL, err := GetURLFor(
 		fmt.Sprintf("biovoice/subscription/%s",
 			url.PathEscape(string(request.GetRecipient()))),
@@ -36,7 +43,9 @@ func (request *BioVoiceRequest) DeleteSubscription() error {
 		return err
 	}
 
-	err = GetClient(RegionCurrent, APIKey).Call(
+	err = clientOrDefault(request.client).call(
+		ctx,
+		EndpointBioVoiceDeleteSubscription,
 		http.MethodDelete,
 		apiURL,
 		request,
@@ -53,6 +62,11 @@ func (request *BioVoiceRequest) DeleteSubscription() error {
 
 // CreateRegistration will trigger the creation of a boivoice registration
 func (request *BioVoiceRequest) CreateRegistration() (*BioVoiceResponse, error) {
+	return request.CreateRegistrationContext(context.Background())
+}
+
+// CreateRegistrationContext creates a biovoice registration using ctx for the call
+func (request *BioVoiceRequest) CreateRegistrationContext(ctx context.Context) (*BioVoiceResponse, error) {
 	response := &BioVoiceResponse{}
 
 	apiURL, err := GetURLFor("biovoice/registration")
@@ -60,7 +74,9 @@ func (request *BioVoiceRequest) CreateRegistration() (*BioVoiceResponse, error)
 		return nil, err
 	}
 
-	err = GetClient(RegionCurrent, APIKey).Call(
+	err = clientOrDefault(request.client).call(
+		ctx,
+		EndpointBioVoiceCreateRegistration,
 		http.MethodPost,
 		apiURL,
 		request,
@@ -77,6 +93,11 @@ func (request *BioVoiceRequest) CreateRegistration() (*BioVoiceResponse, error)
 
 // CheckSubscription checks the status of the subscription
 func (request *BioVoiceRequest) CheckSubscription() (*BioVoiceResponse, error) {
+	return request.CheckSubscriptionContext(context.Background())
+}
+
+// CheckSubscriptionContext checks the status of the subscription using ctx for the call
+func (request *BioVoiceRequest) CheckSubscriptionContext(ctx context.Context) (*BioVoiceResponse, error) {
 	response := &BioVoiceResponse{}
 	apiURL, err := GetURLFor(
 		fmt.Sprintf("biovoice/subscription/%s",
@@ -86,7 +107,9 @@ func (request *BioVoiceRequest) CheckSubscription() (*BioVoiceResponse, error) {
 		return nil, err
 	}
 
-	err = GetClient(RegionCurrent, APIKey).Call(
+	err = clientOrDefault(request.client).call(
+		ctx,
+		EndpointBioVoiceCheckSubscription,
 		http.MethodGet,
 		apiURL,
 		nil,
@@ -103,6 +126,11 @@ func (request *BioVoiceRequest) CheckSubscription() (*BioVoiceResponse, error) {
 
 // CheckRegistration checks the status of the registration
 func (request *BioVoiceRequest) CheckRegistration() (*BioVoiceResponse, error) {
+	return request.CheckRegistrationContext(context.Background())
+}
+
+// CheckRegistrationContext checks the status of the registration using ctx for the call
+func (request *BioVoiceRequest) CheckRegistrationContext(ctx context.Context) (*BioVoiceResponse, error) {
 	response := &BioVoiceResponse{}
 	apiURL, err := GetURLFor(
 		fmt.Sprintf("biovoice/registration/%s",
@@ -112,7 +140,9 @@ func (request *BioVoiceRequest) CheckRegistration() (*BioVoiceResponse, error) {
 		return nil, err
 	}
 
-	err = GetClient(RegionCurrent, APIKey).Call(
+	err = clientOrDefault(request.client).call(
+		ctx,
+		EndpointBioVoiceCheckRegistration,
 		http.MethodGet,
 		apiURL,
 		nil,
@@ -242,6 +272,11 @@ func (response BioVoiceResponse) GetRecipient() Recipient {
 
 // NewBioVoiceRequest creates a new BioVoiceRequest
 func NewBioVoiceRequest(recipient interface{}) (*BioVoiceRequest, error) {
+	return DefaultClient.NewBioVoiceRequest(recipient)
+}
+
+// NewBioVoiceRequest creates a new BioVoiceRequest that will use the client
+func (c *Client) NewBioVoiceRequest(recipient interface{}) (*BioVoiceRequest, error) {
 	r, err := convertRecipients(recipient)
 	if err != nil {
 		return nil, err
@@ -249,14 +284,23 @@ func NewBioVoiceRequest(recipient interface{}) (*BioVoiceRequest, error) {
 	if len(r) != 1 {
 		return nil, fmt.Errorf("need at least 1 recipient got [%d]", len(r))
 	}
+	parsed, err := ParseRecipient(string(r[0]), c.getDefaultCountry())
+	if err != nil {
+		return nil, err
+	}
 
-	request := &BioVoiceRequest{recipient: r[0]}
+	request := &BioVoiceRequest{recipient: parsed, client: c}
 	return request, nil
 }
 
 // BioVoiceCreateRegistration creates new biovoice registration for a recipient
 func BioVoiceCreateRegistration(recipient interface{}) (*BioVoiceResponse, error) {
-	request, err := NewBioVoiceRequest(recipient)
+	return DefaultClient.BioVoiceCreateRegistration(recipient)
+}
+
+// BioVoiceCreateRegistration creates new biovoice registration for a recipient using the client
+func (c *Client) BioVoiceCreateRegistration(recipient interface{}) (*BioVoiceResponse, error) {
+	request, err := c.NewBioVoiceRequest(recipient)
 	if err != nil {
 		return nil, err
 	}
@@ -265,7 +309,12 @@ func BioVoiceCreateRegistration(recipient interface{}) (*BioVoiceResponse, error
 
 // BioVoiceCheckRegistration checks the biovoice registration of a recipient
 func BioVoiceCheckRegistration(recipient interface{}) (*BioVoiceResponse, error) {
-	request, err := NewBioVoiceRequest(recipient)
+	return DefaultClient.BioVoiceCheckRegistration(recipient)
+}
+
+// BioVoiceCheckRegistration checks the biovoice registration of a recipient using the client
+func (c *Client) BioVoiceCheckRegistration(recipient interface{}) (*BioVoiceResponse, error) {
+	request, err := c.NewBioVoiceRequest(recipient)
 	if err != nil {
 		return nil, err
 	}
@@ -274,7 +323,12 @@ func BioVoiceCheckRegistration(recipient interface{}) (*BioVoiceResponse, error)
 
 // BioVoiceCheckSubscription checks the biovoice subscription of a recipient
 func BioVoiceCheckSubscription(recipient interface{}) (*BioVoiceResponse, error) {
-	request, err := NewBioVoiceRequest(recipient)
+	return DefaultClient.BioVoiceCheckSubscription(recipient)
+}
+
+// BioVoiceCheckSubscription checks the biovoice subscription of a recipient using the client
+func (c *Client) BioVoiceCheckSubscription(recipient interface{}) (*BioVoiceResponse, error) {
+	request, err := c.NewBioVoiceRequest(recipient)
 	if err != nil {
 		return nil, err
 	}
@@ -283,7 +337,12 @@ func BioVoiceCheckSubscription(recipient interface{}) (*BioVoiceResponse, error)
 
 // BioVoiceDeleteSubscription will delete the biovoice for the identifier supplied
 func BioVoiceDeleteSubscription(recipient interface{}) error {
-	request, err := NewBioVoiceRequest(recipient)
+	return DefaultClient.BioVoiceDeleteSubscription(recipient)
+}
+
+// BioVoiceDeleteSubscription will delete the biovoice for the recipient using the client
+func (c *Client) BioVoiceDeleteSubscription(recipient interface{}) error {
+	request, err := c.NewBioVoiceRequest(recipient)
 	if err != nil {
 		return err
 	}
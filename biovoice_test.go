@@ -58,7 +58,7 @@ func TestBioVoiceCheckRegistration(t *testing.T) {
 		RegistrationID string
 		Host           string
 	}{
-		Recipient:      twizo.Recipient("0000000000"),
+		Recipient:      twizo.Recipient("31600000000"),
 		RegistrationID: "00000.B000fff000fff0000.00000000",
 		Host:           twizo.GetHostForRegion(twizo.RegionCurrent),
 	}
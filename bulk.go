@@ -0,0 +1,354 @@
+package twizo
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBulkChunkSize is the default amount of recipients per submit of a BulkSmsSender
+const DefaultBulkChunkSize = 1000
+
+// DefaultBulkConcurrency is the default amount of concurrent submits of a BulkSmsSender
+const DefaultBulkConcurrency = 4
+
+// RecipientIterator returns the recipients one by one, Next returns io.EOF after the last one
+type RecipientIterator interface {
+	Next() (Recipient, error)
+}
+
+type sliceRecipientIterator struct {
+	recipients []Recipient
+}
+
+// RecipientsFromSlice returns an iterator over recipients
+func RecipientsFromSlice(recipients []Recipient) RecipientIterator {
+	return &sliceRecipientIterator{recipients: recipients}
+}
+
+func (i *sliceRecipientIterator) Next() (Recipient, error) {
+	if len(i.recipients) == 0 {
+		return "", io.EOF
+	}
+	recipient := i.recipients[0]
+	i.recipients = i.recipients[1:]
+	return recipient, nil
+}
+
+type csvRecipientIterator struct {
+	reader *csv.Reader
+	column int
+}
+
+// RecipientsFromCSV returns an iterator over the recipients in column (starting at 0) of reader, empty
+// values are skipped. Read the header first when the csv has one.
+func RecipientsFromCSV(reader *csv.Reader, column int) RecipientIterator {
+	return &csvRecipientIterator{reader: reader, column: column}
+}
+
+func (i *csvRecipientIterator) Next() (Recipient, error) {
+	for {
+		record, err := i.reader.Read()
+		if err != nil {
+			return "", err
+		}
+		if i.column >= len(record) {
+			return "", fmt.Errorf("csv record %v has no column [%d]", record, i.column)
+		}
+		if value := strings.TrimSpace(record[i.column]); value != "" {
+			return Recipient(value), nil
+		}
+	}
+}
+
+// BulkSmsChunkError is the error of one submit of a BulkSmsSender, or of the invalid recipients of a
+// chunk in which case Err matches ErrInvalidRecipient
+type BulkSmsChunkError struct {
+	// Chunk is the index of the chunk, starting at 0
+	Chunk int
+
+	// Recipients are the recipients of the error, none of them was sent the message
+	Recipients []Recipient
+
+	Err error
+}
+
+// Error returns the error message
+func (e *BulkSmsChunkError) Error() string {
+	return fmt.Sprintf("chunk [%d] of [%d] recipients failed: %v", e.Chunk, len(e.Recipients), e.Err)
+}
+
+// Unwrap returns the error of the submit
+func (e *BulkSmsChunkError) Unwrap() error {
+	return e.Err
+}
+
+// BulkSmsResult contains the responses and errors of all submits of a BulkSmsSender
+type BulkSmsResult struct {
+	// Responses contains the responses of all submitted chunks, in order of the chunks
+	Responses []SmsResponse
+
+	// Errors contains the failed chunks, in order of the chunks
+	Errors []*BulkSmsChunkError
+
+	// Submitted is the amount of recipients submitted successfully
+	Submitted int
+}
+
+// Failed returns the recipients of the failed chunks
+func (result *BulkSmsResult) Failed() []Recipient {
+	var recipients []Recipient
+	for _, err := range result.Errors {
+		recipients = append(recipients, err.Recipients...)
+	}
+	return recipients
+}
+
+// BulkSmsSender submits a message to a large amount of recipients, split into chunks
+type BulkSmsSender struct {
+	// ChunkSize is the amount of recipients per submit, DefaultBulkChunkSize when 0
+	ChunkSize int
+
+	// Concurrency is the amount of concurrent submits, DefaultBulkConcurrency when 0
+	Concurrency int
+
+	// MessagesPerSecond limits the amount of messages submitted per second, a recipient counts as the
+	// amount of segments of the body. A chunk is submitted at once, after which the next chunk waits
+	// until the rate allows its messages. There is no limit when 0.
+	MessagesPerSecond float64
+}
+
+// NewBulkSmsSender creates a new bulk sender with the default chunk size and concurrency
+func NewBulkSmsSender() *BulkSmsSender {
+	return &BulkSmsSender{
+		ChunkSize:   DefaultBulkChunkSize,
+		Concurrency: DefaultBulkConcurrency,
+	}
+}
+
+type bulkChunk struct {
+	index      int
+	recipients []Recipient
+}
+
+// Send submits request to recipients, the recipients set on request are ignored
+func (s *BulkSmsSender) Send(
+	ctx context.Context,
+	request *SmsRequest,
+	recipients []Recipient,
+) (*BulkSmsResult, error) {
+	return s.SendIterator(ctx, request, RecipientsFromSlice(recipients))
+}
+
+// SendIterator submits request to the recipients returned by recipients, the recipients set on
+// request are ignored. Failed submits are returned in the Errors of the result, an error is returned
+// when reading the recipients fails or ctx is done, together with the result of the chunks submitted.
+func (s *BulkSmsSender) SendIterator(
+	ctx context.Context,
+	request *SmsRequest,
+	recipients RecipientIterator,
+) (*BulkSmsResult, error) {
+	info, err := request.SegmentInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	chunkSize := s.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = DefaultBulkChunkSize
+	}
+	concurrency := s.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultBulkConcurrency
+	}
+	limiter := &messageLimiter{rate: s.MessagesPerSecond}
+
+	country := clientOrDefault(request.client).getDefaultCountry()
+
+	var mu sync.Mutex
+	responses := map[int][]SmsResponse{}
+	failed := map[int][]*BulkSmsChunkError{}
+	result := &BulkSmsResult{}
+
+	var wg sync.WaitGroup
+	chunks := make(chan bulkChunk)
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for chunk := range chunks {
+				valid, invalid := parseBulkRecipients(chunk, country)
+				var items []SmsResponse
+				var err error
+				if len(valid) > 0 {
+					items, err = s.submit(ctx, request, valid, info.Segments, limiter)
+				}
+				mu.Lock()
+				if invalid != nil {
+					failed[chunk.index] = append(failed[chunk.index], invalid)
+				}
+				if err != nil {
+					failed[chunk.index] = append(failed[chunk.index], &BulkSmsChunkError{
+						Chunk:      chunk.index,
+						Recipients: valid,
+						Err:        err,
+					})
+				} else {
+					responses[chunk.index] = items
+					result.Submitted += len(valid)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	count, err := produceChunks(ctx, recipients, chunkSize, chunks)
+	close(chunks)
+	wg.Wait()
+
+	for i := 0; i < count; i++ {
+		result.Errors = append(result.Errors, failed[i]...)
+		result.Responses = append(result.Responses, responses[i]...)
+	}
+
+	return result, err
+}
+
+// parseBulkRecipients normalises the recipients of chunk using ParseRecipient, the invalid recipients
+// are returned as error
+func parseBulkRecipients(chunk bulkChunk, country string) ([]Recipient, *BulkSmsChunkError) {
+	valid := make([]Recipient, 0, len(chunk.recipients))
+	var invalid []Recipient
+	var first error
+	for _, input := range chunk.recipients {
+		recipient, err := ParseRecipient(string(input), country)
+		if err != nil {
+			invalid = append(invalid, input)
+			if first == nil {
+				first = err
+			}
+			continue
+		}
+		valid = append(valid, recipient)
+	}
+
+	if invalid == nil {
+		return valid, nil
+	}
+	if len(invalid) > 1 {
+		first = fmt.Errorf("[%d] invalid recipients, first: %w", len(invalid), first)
+	}
+	return valid, &BulkSmsChunkError{Chunk: chunk.index, Recipients: invalid, Err: first}
+}
+
+// produceChunks reads recipients into chunks of chunkSize and sends them on chunks, the amount of
+// chunks sent is returned
+func produceChunks(
+	ctx context.Context,
+	recipients RecipientIterator,
+	chunkSize int,
+	chunks chan<- bulkChunk,
+) (int, error) {
+	count := 0
+	send := func(chunk []Recipient) error {
+		select {
+		case chunks <- bulkChunk{index: count, recipients: chunk}:
+			count++
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	chunk := make([]Recipient, 0, chunkSize)
+	for {
+		recipient, err := recipients.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return count, err
+		}
+
+		chunk = append(chunk, recipient)
+		if len(chunk) == chunkSize {
+			if err := send(chunk); err != nil {
+				return count, err
+			}
+			chunk = make([]Recipient, 0, chunkSize)
+		}
+	}
+
+	if len(chunk) > 0 {
+		if err := send(chunk); err != nil {
+			return count, err
+		}
+	}
+	return count, ctx.Err()
+}
+
+// submit submits request to the recipients of one chunk, after waiting for the limiter
+func (s *BulkSmsSender) submit(
+	ctx context.Context,
+	request *SmsRequest,
+	recipients []Recipient,
+	segments int,
+	limiter *messageLimiter,
+) ([]SmsResponse, error) {
+	if err := limiter.wait(ctx, len(recipients)*segments); err != nil {
+		return nil, err
+	}
+
+	chunk := *request
+	chunk.recipients = recipients
+	responses, err := chunk.SubmitContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if responses.Responses == nil {
+		return nil, nil
+	}
+	return *responses.Responses, nil
+}
+
+// messageLimiter limits the amount of messages per second, every call reserves the time needed for its
+// messages and waits for the reservations made before
+type messageLimiter struct {
+	rate float64
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+func (l *messageLimiter) wait(ctx context.Context, messages int) error {
+	if l.rate <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	start := l.next
+	if start.Before(now) {
+		start = now
+	}
+	l.next = start.Add(time.Duration(float64(messages) / l.rate * float64(time.Second)))
+	l.mu.Unlock()
+
+	wait := start.Sub(now)
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
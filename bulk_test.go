@@ -0,0 +1,156 @@
+package twizo_test
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	twizo "github.com/twizoapi/lib-api-go"
+	. "github.com/twizoapi/lib-api-go/testing"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+func init() {
+	twizo.APIKey = TestAPIKey
+	twizo.RegionCurrent = TestRegion
+}
+
+// registerBulkSubmit mocks the simple submit, every recipient gets a message with the recipient as id,
+// submits containing the recipient 31600000666 are rejected
+func registerBulkSubmit(submits *int) {
+	var mu sync.Mutex
+	httpmock.RegisterResponder(
+		http.MethodPost,
+		fmt.Sprintf("https://%s/%s/sms/submitsimple", twizo.GetHostForRegion(TestRegion), twizo.ClientAPIVersion),
+		func(req *http.Request) (*http.Response, error) {
+			mu.Lock()
+			*submits++
+			mu.Unlock()
+
+			body, _ := ioutil.ReadAll(req.Body)
+			var data struct {
+				Recipients []string `json:"recipients"`
+			}
+			if err := json.Unmarshal(body, &data); err != nil {
+				return httpmock.NewStringResponse(http.StatusBadRequest, ""), nil
+			}
+
+			var items []string
+			for _, recipient := range data.Recipients {
+				if recipient == "31600000666" {
+					return newProblemResponder(http.StatusUnprocessableEntity, 0)(req)
+				}
+				items = append(items, fmt.Sprintf(`{"messageId":"%s","recipient":"%s"}`, recipient, recipient))
+			}
+			return httpmock.NewStringResponse(
+				http.StatusCreated,
+				fmt.Sprintf(`{"_embedded":{"items":[%s]}}`, strings.Join(items, ",")),
+			), nil
+		},
+	)
+}
+
+func TestBulkSmsSender(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	submits := 0
+	registerBulkSubmit(&submits)
+
+	request, err := twizo.NewSmsRequest(nil, "Message", "Sender")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sender := twizo.NewBulkSmsSender()
+	sender.ChunkSize = 2
+	sender.Concurrency = 2
+
+	result, err := sender.Send(
+		context.Background(),
+		request,
+		[]twizo.Recipient{"31600000001", "31600000002", "31600000666", "+31 6 00000004", "31600000005", "12"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if submits != 3 {
+		t.Fatalf("Invalid amount of submits expecting [3] got [%d]", submits)
+	}
+	if result.Submitted != 3 {
+		t.Fatalf("Invalid submitted expecting [3] got [%d]", result.Submitted)
+	}
+
+	var messageIDs []string
+	for _, response := range result.Responses {
+		messageIDs = append(messageIDs, response.GetMessageID())
+	}
+	if fmt.Sprint(messageIDs) != "[31600000001 31600000002 31600000005]" {
+		t.Fatalf("Invalid responses expecting [[31600000001 31600000002 31600000005]] got [%v]", messageIDs)
+	}
+
+	// the submit of chunk 1 fails, the invalid recipient of chunk 2 is not sent
+	if len(result.Errors) != 2 || result.Errors[0].Chunk != 1 || result.Errors[1].Chunk != 2 {
+		t.Fatalf("Invalid errors expecting chunks [1 2] to fail got [%v]", result.Errors)
+	}
+	if !errors.Is(result.Errors[0], twizo.ErrValidation) {
+		t.Fatalf("Expecting chunk error to match [%v] got [%v]", twizo.ErrValidation, result.Errors[0])
+	}
+	if !errors.Is(result.Errors[1], twizo.ErrInvalidRecipient) {
+		t.Fatalf("Expecting chunk error to match [%v] got [%v]", twizo.ErrInvalidRecipient, result.Errors[1])
+	}
+	if fmt.Sprint(result.Failed()) != "[31600000666 31600000004 12]" {
+		t.Fatalf("Invalid failed recipients expecting [[31600000666 31600000004 12]] got [%v]", result.Failed())
+	}
+}
+
+func TestBulkSmsSenderCSVAndRate(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	submits := 0
+	registerBulkSubmit(&submits)
+
+	request, err := twizo.NewSmsRequest(nil, "Message", "Sender")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reader := csv.NewReader(strings.NewReader(
+		"name,number\na,+31600000001\nb,\nc,+31600000003\nd,+31600000004\ne,+31600000005\nf,+31600000006\n"+
+			"g,+31600000007\n",
+	))
+	if _, err := reader.Read(); err != nil {
+		t.Fatal(err)
+	}
+
+	sender := twizo.NewBulkSmsSender()
+	sender.ChunkSize = 2
+	sender.Concurrency = 3
+	sender.MessagesPerSecond = 100
+
+	start := time.Now()
+	result, err := sender.SendIterator(context.Background(), request, twizo.RecipientsFromCSV(reader, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the empty number is skipped, six recipients make three chunks of two messages, at 100 messages per
+	// second the last chunk waits for the two chunks before it
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Fatalf("Invalid duration expecting at least [40ms] got [%s]", elapsed)
+	}
+	if result.Submitted != 6 || len(result.Errors) != 0 {
+		t.Fatalf("Invalid result expecting [6] submitted without errors got [%d] %v", result.Submitted, result.Errors)
+	}
+}
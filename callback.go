@@ -0,0 +1,133 @@
+package twizo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// DefaultCallbackMaxBodySize is the maximum size of a callback body when CallbackHandler.MaxBodySize is 0
+const DefaultCallbackMaxBodySize = 1 << 20
+
+// ErrUnknownCallback is returned by ParseCallback when the payload is not a sms or numberlookup
+var ErrUnknownCallback = errors.New("twizo: unknown callback payload")
+
+// CallbackHandler is a http.Handler receiving the callbacks twizo sends to the callback url of
+// a sms or numberlookup request, see SetCallbackURL. The payload is passed to the handler func
+// for its type.
+//
+// Callbacks are acknowledged with 200 when the handler func returns nil, when it returns an error
+// 500 is sent so twizo will try again later. Malformed or unknown payloads are answered with 400,
+// a payload without a handler func with 501.
+type CallbackHandler struct {
+	// Sms is called with the status update of a sms
+	Sms func(ctx context.Context, response *SmsResponse) error
+
+	// NumberLookup is called with the result of a numberlookup
+	NumberLookup func(ctx context.Context, response *NumberLookupResponse) error
+
+	// Client is set on the received responses and used for logging, DefaultClient when nil
+	Client *Client
+
+	// MaxBodySize is the maximum size of the body, DefaultCallbackMaxBodySize when 0
+	MaxBodySize int64
+}
+
+// ParseCallback parses the body of a callback, the result is a *SmsResponse or a *NumberLookupResponse
+func ParseCallback(body []byte) (interface{}, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, err
+	}
+
+	// numberlookup results contain the number, sms messages the recipient
+	if _, ok := fields["number"]; ok {
+		response := &NumberLookupResponse{}
+		if err := json.Unmarshal(body, response); err != nil {
+			return nil, err
+		}
+		return response, nil
+	}
+	if _, ok := fields["recipient"]; ok {
+		response := &SmsResponse{}
+		if err := json.Unmarshal(body, response); err != nil {
+			return nil, err
+		}
+		return response, nil
+	}
+
+	return nil, ErrUnknownCallback
+}
+
+// ServeHTTP handles a callback request
+func (h *CallbackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	maxBodySize := h.MaxBodySize
+	if maxBodySize <= 0 {
+		maxBodySize = DefaultCallbackMaxBodySize
+	}
+
+	// read one byte more than allowed, so we know when the body is too large
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxBodySize+1))
+	if err != nil {
+		h.fail(r.Context(), w, http.StatusBadRequest, err)
+		return
+	}
+	if int64(len(body)) > maxBodySize {
+		h.fail(r.Context(), w, http.StatusRequestEntityTooLarge, nil)
+		return
+	}
+
+	payload, err := ParseCallback(bytes.TrimSpace(body))
+	if err != nil {
+		h.fail(r.Context(), w, http.StatusBadRequest, err)
+		return
+	}
+
+	client := clientOrDefault(h.Client)
+	switch response := payload.(type) {
+	case *SmsResponse:
+		if h.Sms == nil {
+			h.fail(r.Context(), w, http.StatusNotImplemented, nil)
+			return
+		}
+		response.client = client
+		err = h.Sms(r.Context(), response)
+	case *NumberLookupResponse:
+		if h.NumberLookup == nil {
+			h.fail(r.Context(), w, http.StatusNotImplemented, nil)
+			return
+		}
+		response.client = client
+		err = h.NumberLookup(r.Context(), response)
+	}
+	if err != nil {
+		h.fail(r.Context(), w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// fail answers the callback with status and logs err
+func (h *CallbackHandler) fail(ctx context.Context, w http.ResponseWriter, status int, err error) {
+	client := clientOrDefault(h.Client)
+	if logger := client.getLogger(); logger != nil {
+		args := []interface{}{"status", status}
+		if err != nil {
+			args = append(args, "error", client.logError(err))
+		}
+		logger.DebugContext(ctx, "twizo callback failed", args...)
+	}
+
+	http.Error(w, http.StatusText(status), status)
+}
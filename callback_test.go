@@ -0,0 +1,120 @@
+package twizo_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	twizo "github.com/twizoapi/lib-api-go"
+	. "github.com/twizoapi/lib-api-go/testing"
+)
+
+func init() {
+	twizo.APIKey = TestAPIKey
+	twizo.RegionCurrent = TestRegion
+}
+
+func sendCallback(handler http.Handler, method string, body string) int {
+	req := httptest.NewRequest(method, "/callback", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	return rec.Code
+}
+
+func TestCallbackHandlerSms(t *testing.T) {
+	var received *twizo.SmsResponse
+	handler := &twizo.CallbackHandler{
+		Sms: func(ctx context.Context, response *twizo.SmsResponse) error {
+			received = response
+			return nil
+		},
+	}
+
+	code := sendCallback(handler, http.MethodPost, `{
+		"applicationTag":"Twizo",
+		"body":"Body",
+		"callbackUrl":"https://example.com/callback",
+		"messageId":"messageId",
+		"recipient":"31600000000",
+		"sender":"Twizo",
+		"status":"Delivered",
+		"statusCode":1
+	}`)
+
+	if code != http.StatusOK {
+		t.Fatalf("Invalid status code expecting [%d] got [%d]", http.StatusOK, code)
+	}
+	if received == nil {
+		t.Fatal("Expecting sms handler to be called")
+	}
+	if received.GetMessageID() != "messageId" {
+		t.Fatalf("Invalid messageId expecting [messageId] got [%s]", received.GetMessageID())
+	}
+	if received.GetStatusCode() != twizo.SmsStatusCodeDelivered {
+		t.Fatalf("Invalid statusCode expecting [%d] got [%d]", twizo.SmsStatusCodeDelivered, received.GetStatusCode())
+	}
+	if received.GetCallbackURL() == nil || received.GetCallbackURL().Host != "example.com" {
+		t.Fatalf("Invalid callbackUrl expecting [https://example.com/callback] got [%v]", received.GetCallbackURL())
+	}
+}
+
+func TestCallbackHandlerNumberLookup(t *testing.T) {
+	var received *twizo.NumberLookupResponse
+	handler := &twizo.CallbackHandler{
+		NumberLookup: func(ctx context.Context, response *twizo.NumberLookupResponse) error {
+			received = response
+			return nil
+		},
+	}
+
+	code := sendCallback(handler, http.MethodPost, `{
+		"messageId":"messageId",
+		"number":"31600000000",
+		"status":"Delivered",
+		"statusCode":1
+	}`)
+
+	if code != http.StatusOK {
+		t.Fatalf("Invalid status code expecting [%d] got [%d]", http.StatusOK, code)
+	}
+	if received == nil {
+		t.Fatal("Expecting numberlookup handler to be called")
+	}
+	if received.GetNumber() != "31600000000" {
+		t.Fatalf("Invalid number expecting [31600000000] got [%s]", received.GetNumber())
+	}
+}
+
+func TestCallbackHandlerStatusCodes(t *testing.T) {
+	handler := &twizo.CallbackHandler{
+		Sms: func(ctx context.Context, response *twizo.SmsResponse) error {
+			return errors.New("database down")
+		},
+		MaxBodySize: 64,
+	}
+
+	tests := []struct {
+		method string
+		body   string
+		expect int
+	}{
+		{http.MethodGet, ``, http.StatusMethodNotAllowed},
+		{http.MethodPost, `{"recipient":`, http.StatusBadRequest},
+		{http.MethodPost, `{"unknown":"payload"}`, http.StatusBadRequest},
+		{http.MethodPost, `{"body":"` + strings.Repeat("x", 64) + `"}`, http.StatusRequestEntityTooLarge},
+		{http.MethodPost, `{"number":"31600000000"}`, http.StatusNotImplemented},
+		{http.MethodPost, `{"recipient":"31600000000"}`, http.StatusInternalServerError},
+	}
+
+	for _, test := range tests {
+		code := sendCallback(handler, test.method, test.body)
+		if code != test.expect {
+			t.Errorf("Invalid status code for [%s %s] expecting [%d] got [%d]", test.method, test.body, test.expect, code)
+		}
+	}
+}
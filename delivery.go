@@ -0,0 +1,249 @@
+package twizo
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultDeliveryTrackerInterval is the default interval between the status lookups of a DeliveryTracker
+const DefaultDeliveryTrackerInterval = 10 * time.Second
+
+// DeliveryTracker waits until all submitted messages reached a final status, see SmsStatusCode.IsFinal.
+//
+// The status of the messages is looked up every Interval. When the messages were sent with a callback
+// or polling result type, pass Update as handler to a CallbackHandler or SmsPoller and set Interval to 0.
+type DeliveryTracker struct {
+	// Interval between the status lookups of the messages without a final status, 0 disables the lookups
+	Interval time.Duration
+
+	mu       sync.Mutex
+	messages []*trackedMessage
+	byID     map[string]*trackedMessage
+	updated  chan struct{}
+}
+
+type trackedMessage struct {
+	response SmsResponse
+	err      error
+}
+
+// DeliveryOutcome is the outcome of the messages sent to one recipient, a long body is sent as
+// multiple messages
+type DeliveryOutcome struct {
+	Recipient  Recipient
+	MessageIDs []string
+
+	// StatusCode is delivered when all messages were delivered, otherwise it is the status of the
+	// first failed message, or of the first message still on its way
+	StatusCode SmsStatusCode
+	StatusMsg  string
+	ReasonCode *int
+
+	// Final is true when the outcome will not change anymore
+	Final bool
+
+	// Err is the error of the last failed status lookup, if any
+	Err error
+}
+
+// IsDelivered returns true when all messages to the recipient were delivered
+func (outcome DeliveryOutcome) IsDelivered() bool {
+	return outcome.StatusCode == SmsStatusCodeDelivered
+}
+
+// DeliveryReport contains the outcome per recipient, in the order of the submitted messages
+type DeliveryReport struct {
+	Outcomes []DeliveryOutcome
+}
+
+// Outcome returns the outcome for recipient
+func (report DeliveryReport) Outcome(recipient Recipient) (DeliveryOutcome, bool) {
+	for _, outcome := range report.Outcomes {
+		if outcome.Recipient == recipient {
+			return outcome, true
+		}
+	}
+	return DeliveryOutcome{}, false
+}
+
+// IsComplete returns true when all outcomes are final
+func (report DeliveryReport) IsComplete() bool {
+	for _, outcome := range report.Outcomes {
+		if !outcome.Final {
+			return false
+		}
+	}
+	return true
+}
+
+// NewDeliveryTracker creates a new tracker for the submitted messages in responses
+func NewDeliveryTracker(responses *SmsResponses) *DeliveryTracker {
+	tracker := &DeliveryTracker{
+		Interval: DefaultDeliveryTrackerInterval,
+		byID:     map[string]*trackedMessage{},
+		updated:  make(chan struct{}, 1),
+	}
+	if responses == nil || responses.Responses == nil {
+		return tracker
+	}
+
+	for _, response := range *responses.Responses {
+		message := &trackedMessage{response: response}
+		tracker.messages = append(tracker.messages, message)
+		tracker.byID[response.GetMessageID()] = message
+	}
+
+	return tracker
+}
+
+// Update updates the status of a tracked message, messages that are not tracked are ignored. It
+// can be used as handler of a CallbackHandler or SmsPoller.
+func (t *DeliveryTracker) Update(ctx context.Context, response *SmsResponse) error {
+	t.mu.Lock()
+	message, ok := t.byID[response.GetMessageID()]
+	if ok {
+		message.response = *response
+		message.err = nil
+	}
+	t.mu.Unlock()
+
+	if ok {
+		select {
+		case t.updated <- struct{}{}:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// Wait waits until all outcomes are final or ctx is done, the report is returned in both cases. When
+// ctx is done before all outcomes are final the error of ctx is returned.
+func (t *DeliveryTracker) Wait(ctx context.Context) (*DeliveryReport, error) {
+	var tick <-chan time.Time
+	if t.Interval > 0 {
+		ticker := time.NewTicker(t.Interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		report := t.Report()
+		if report.IsComplete() {
+			return report, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return report, ctx.Err()
+		case <-t.updated:
+		case <-tick:
+			t.lookup(ctx, report)
+		}
+	}
+}
+
+// lookup requests the status of the messages of the outcomes that are not final
+func (t *DeliveryTracker) lookup(ctx context.Context, report *DeliveryReport) {
+	for _, outcome := range report.Outcomes {
+		if outcome.Final {
+			continue
+		}
+
+		for _, messageID := range outcome.MessageIDs {
+			t.mu.Lock()
+			message := t.byID[messageID]
+			response := message.response
+			t.mu.Unlock()
+
+			if response.GetStatusCode().IsFinal() {
+				continue
+			}
+
+			err := response.StatusContext(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+
+			t.mu.Lock()
+			if err != nil {
+				message.err = err
+			} else {
+				message.response = response
+				message.err = nil
+			}
+			t.mu.Unlock()
+		}
+	}
+}
+
+// Report returns the current outcome per recipient
+func (t *DeliveryTracker) Report() *DeliveryReport {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	report := &DeliveryReport{}
+	index := map[Recipient]int{}
+	var messages [][]*trackedMessage
+	for _, message := range t.messages {
+		recipient := message.response.GetRecipient()
+		i, ok := index[recipient]
+		if !ok {
+			i = len(messages)
+			index[recipient] = i
+			messages = append(messages, nil)
+		}
+		messages[i] = append(messages[i], message)
+	}
+
+	for _, parts := range messages {
+		report.Outcomes = append(report.Outcomes, newDeliveryOutcome(parts))
+	}
+
+	return report
+}
+
+func newDeliveryOutcome(parts []*trackedMessage) DeliveryOutcome {
+	outcome := DeliveryOutcome{
+		Recipient: parts[0].response.GetRecipient(),
+	}
+
+	var failed, pending *SmsResponse
+	for _, part := range parts {
+		response := &part.response
+		outcome.MessageIDs = append(outcome.MessageIDs, response.GetMessageID())
+		if part.err != nil {
+			outcome.Err = part.err
+		}
+
+		switch {
+		case response.GetStatusCode() == SmsStatusCodeDelivered:
+		case response.GetStatusCode().IsFinal():
+			if failed == nil {
+				failed = response
+			}
+		default:
+			if pending == nil {
+				pending = response
+			}
+		}
+	}
+
+	// one failed message is enough for the outcome to be final
+	result := &parts[0].response
+	switch {
+	case failed != nil:
+		result = failed
+		outcome.Final = true
+	case pending != nil:
+		result = pending
+	default:
+		outcome.Final = true
+	}
+	outcome.StatusCode = result.GetStatusCode()
+	outcome.StatusMsg = result.GetStatusMsg()
+	outcome.ReasonCode = result.GetReasonCode()
+
+	return outcome
+}
@@ -0,0 +1,139 @@
+package twizo_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	twizo "github.com/twizoapi/lib-api-go"
+	. "github.com/twizoapi/lib-api-go/testing"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+func init() {
+	twizo.APIKey = TestAPIKey
+	twizo.RegionCurrent = TestRegion
+}
+
+func smsStatusURL(messageID string) string {
+	return fmt.Sprintf(
+		"https://%s/%s/sms/submit/%s",
+		twizo.GetHostForRegion(TestRegion),
+		twizo.ClientAPIVersion,
+		messageID,
+	)
+}
+
+func smsStatusJSON(messageID string, recipient string, statusCode twizo.SmsStatusCode) string {
+	return fmt.Sprintf(
+		`{"messageId":"%s","recipient":"%s","statusCode":%d,"_links":{"self":{"href":"%s"}}}`,
+		messageID,
+		recipient,
+		statusCode,
+		smsStatusURL(messageID),
+	)
+}
+
+func newTrackedResponses(t *testing.T) *twizo.SmsResponses {
+	responses := &twizo.SmsResponses{}
+	err := json.Unmarshal(
+		[]byte(fmt.Sprintf(
+			`{"_embedded":{"items":[%s,%s,%s]}}`,
+			smsStatusJSON("one-1", "31600000001", twizo.SmsStatusCodeNoStatus),
+			smsStatusJSON("one-2", "31600000001", twizo.SmsStatusCodeNoStatus),
+			smsStatusJSON("two", "31600000002", twizo.SmsStatusCodeNoStatus),
+		)),
+		responses,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return responses
+}
+
+func TestDeliveryTrackerLookup(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	statuses := map[string]twizo.SmsStatusCode{
+		"one-1": twizo.SmsStatusCodeDelivered,
+		"one-2": twizo.SmsStatusCodeDelivered,
+		"two":   twizo.SmsStatusCodeRejected,
+	}
+	for messageID, statusCode := range statuses {
+		recipient := "31600000001"
+		if messageID == "two" {
+			recipient = "31600000002"
+		}
+		HTTPMockSend(
+			http.MethodGet,
+			smsStatusURL(messageID),
+			http.StatusOK,
+			smsStatusJSON(messageID, recipient, statusCode),
+			nil,
+		)
+	}
+
+	tracker := twizo.NewDeliveryTracker(newTrackedResponses(t))
+	tracker.Interval = time.Millisecond
+
+	report, err := tracker.Wait(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Outcomes) != 2 {
+		t.Fatalf("Invalid amount of outcomes expecting [2] got [%d]", len(report.Outcomes))
+	}
+
+	one, _ := report.Outcome("31600000001")
+	if !one.IsDelivered() || fmt.Sprint(one.MessageIDs) != "[one-1 one-2]" {
+		t.Fatalf("Invalid outcome expecting delivered [one-1 one-2] got [%d] %v", one.StatusCode, one.MessageIDs)
+	}
+	two, _ := report.Outcome("31600000002")
+	if two.StatusCode != twizo.SmsStatusCodeRejected || !two.Final {
+		t.Fatalf("Invalid outcome expecting final [%d] got [%d]", twizo.SmsStatusCodeRejected, two.StatusCode)
+	}
+}
+
+func TestDeliveryTrackerUpdate(t *testing.T) {
+	tracker := twizo.NewDeliveryTracker(newTrackedResponses(t))
+	tracker.Interval = 0
+
+	for _, update := range []string{
+		smsStatusJSON("one-1", "31600000001", twizo.SmsStatusCodeDelivered),
+		smsStatusJSON("one-2", "31600000001", twizo.SmsStatusCodeBuffered),
+		smsStatusJSON("other", "31600000003", twizo.SmsStatusCodeDelivered),
+		smsStatusJSON("two", "31600000002", twizo.SmsStatusCodeDelivered),
+	} {
+		response, err := twizo.ParseCallback([]byte(update))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := tracker.Update(context.Background(), response.(*twizo.SmsResponse)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	report, err := tracker.Wait(ctx)
+	if err != context.DeadlineExceeded {
+		t.Fatalf("Invalid error expecting [%v] got [%v]", context.DeadlineExceeded, err)
+	}
+	if report.IsComplete() {
+		t.Fatal("Expecting report not to be complete")
+	}
+
+	one, _ := report.Outcome("31600000001")
+	if one.Final || one.StatusCode != twizo.SmsStatusCodeBuffered {
+		t.Fatalf("Invalid outcome expecting pending [%d] got [%d]", twizo.SmsStatusCodeBuffered, one.StatusCode)
+	}
+	if _, ok := report.Outcome("31600000003"); ok {
+		t.Fatal("Expecting untracked message to be ignored")
+	}
+}
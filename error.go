@@ -2,10 +2,68 @@ package twizo
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"sort"
+	"strings"
+	"time"
 )
 
+// Errors returned by the api can be checked against these using errors.Is, for
+// example errors.Is(err, twizo.ErrNotFound)
+var (
+	ErrBadRequest           = errors.New("twizo: bad request")
+	ErrUnauthorized         = errors.New("twizo: unauthorized")
+	ErrForbidden            = errors.New("twizo: forbidden")
+	ErrNotFound             = errors.New("twizo: not found")
+	ErrConflict             = errors.New("twizo: conflict")
+	ErrValidation           = errors.New("twizo: validation failed")
+	ErrLocked               = errors.New("twizo: locked")
+	ErrRateLimited          = errors.New("twizo: rate limited")
+	ErrServer               = errors.New("twizo: server error")
+	ErrInternal             = errors.New("twizo: internal error")
+	ErrInsufficientBalance  = errors.New("twizo: insufficient balance")
+	ErrRecipientBlocked     = errors.New("twizo: recipient blocked")
+	ErrUnsupportedRecipient = errors.New("twizo: recipient not supported")
+	ErrTokenAlreadyVerified = errors.New("twizo: token already verified")
+	ErrTokenExpired         = errors.New("twizo: token expired")
+	ErrTokenInvalid         = errors.New("twizo: token invalid")
+	ErrTokenFailed          = errors.New("twizo: token failed")
+)
+
+var statusErrors = map[int]error{
+	http.StatusBadRequest:          ErrBadRequest,
+	http.StatusUnauthorized:        ErrUnauthorized,
+	http.StatusForbidden:           ErrForbidden,
+	http.StatusNotFound:            ErrNotFound,
+	http.StatusConflict:            ErrConflict,
+	http.StatusUnprocessableEntity: ErrValidation,
+	http.StatusLocked:              ErrLocked,
+	http.StatusTooManyRequests:     ErrRateLimited,
+}
+
+var errorCodeErrors = map[APIErrorCode]error{
+	APIErrorCodeInternal:             ErrInternal,
+	APIErrorCodeValidation:           ErrValidation,
+	APIErrorCodeInsufficientBalance:  ErrInsufficientBalance,
+	APIErrorCodeRecipientBlocked:     ErrRecipientBlocked,
+	APIErrorCodeUnsupportedRecipient: ErrUnsupportedRecipient,
+	APIErrorCodeTokenAlreadyVerified: ErrTokenAlreadyVerified,
+	APIErrorCodeTokenExpired:         ErrTokenExpired,
+	APIErrorCodeTokenInvalid:         ErrTokenInvalid,
+	APIErrorCodeTokenFailed:          ErrTokenFailed,
+}
+
+// isStatusError is true if target is the error that status is reported as
+func isStatusError(status int, target error) bool {
+	if status >= http.StatusInternalServerError {
+		return target == ErrServer
+	}
+	statusError, ok := statusErrors[status]
+	return ok && statusError == target
+}
+
 type jsonAPIError struct {
 	Type      string `json:"type"`
 	Title     string `json:"title"`
@@ -14,6 +72,24 @@ type jsonAPIError struct {
 	ErrorCode int    `json:"errorCode,omitempty"`
 }
 
+// APIErrorCode contains the error code returned by the api in the errorCode field, it
+// further specifies the reason of the error status
+type APIErrorCode int
+
+// Error codes returned by the api
+const (
+	APIErrorCodeNone                 APIErrorCode = 0
+	APIErrorCodeInternal             APIErrorCode = 1
+	APIErrorCodeValidation           APIErrorCode = 2
+	APIErrorCodeInsufficientBalance  APIErrorCode = 3
+	APIErrorCodeRecipientBlocked     APIErrorCode = 4
+	APIErrorCodeUnsupportedRecipient APIErrorCode = 5
+	APIErrorCodeTokenAlreadyVerified APIErrorCode = 101
+	APIErrorCodeTokenExpired         APIErrorCode = 102
+	APIErrorCodeTokenInvalid         APIErrorCode = 103
+	APIErrorCodeTokenFailed          APIErrorCode = 104
+)
+
 // APIError struct
 type APIError struct {
 	title      string
@@ -21,7 +97,8 @@ type APIError struct {
 	status     int
 	lowerError error
 	errorType  string
-	errorCode  int
+	errorCode  APIErrorCode
+	retryAfter time.Duration
 }
 
 // NewAPIError creates a new API error based on the title and the status
@@ -50,38 +127,50 @@ func (e *APIError) copyFrom(j *jsonAPIError) error {
 	e.title = j.Title
 	e.status = j.Status
 	e.detail = j.Detail
+	e.errorCode = APIErrorCode(j.ErrorCode)
 
 	return nil
 }
 
 // Error casts to an actual error struct
-func (e APIError) Error() string {
+func (e *APIError) Error() string {
 	ret, _ := json.Marshal(e)
 	return string(ret)
 }
 
 // Status returns the status of the error
-func (e APIError) Status() int {
+func (e *APIError) Status() int {
 	return e.status
 }
 
 // ErrorCode returns the error code of the request
-func (e APIError) ErrorCode() int {
+func (e *APIError) ErrorCode() APIErrorCode {
 	return e.errorCode
 }
 
+// Type returns the type of the error, an uri describing the problem
+func (e *APIError) Type() string {
+	return e.errorType
+}
+
+// RetryAfter returns the delay the server asked for before trying again, this
+// is only set on rate limited or unavailable responses
+func (e *APIError) RetryAfter() time.Duration {
+	return e.retryAfter
+}
+
 // Detail returns the detail of the error
-func (e APIError) Detail() string {
+func (e *APIError) Detail() string {
 	return e.detail
 }
 
 // Title returns the title of the error
-func (e APIError) Title() string {
+func (e *APIError) Title() string {
 	return e.title
 }
 
 // NotFound is true if the endpoint sent to was not found
-func (e APIError) NotFound() bool {
+func (e *APIError) NotFound() bool {
 	// {"title":"Not Found","status":404,"detail":"Entity not found."} => endpoint correct but we could not find it
 	// {"title":"Not Found","status":404,"detail":"Page not found."} => endpoint incorrect ;(
 
@@ -89,25 +178,56 @@ func (e APIError) NotFound() bool {
 }
 
 // NotAuthorized is true if api key is not valid
-func (e APIError) NotAuthorized() bool {
+func (e *APIError) NotAuthorized() bool {
 	return e.Status() == http.StatusUnauthorized
 }
 
 // Conflict is true if there was a create confict (it already exists)
-func (e APIError) Conflict() bool {
+func (e *APIError) Conflict() bool {
 	return e.Status() == http.StatusConflict
 }
 
 // UnprocessableEntity is true if there was an unprocessable entity (Incorrect validation)
-func (e APIError) UnprocessableEntity() bool {
+func (e *APIError) UnprocessableEntity() bool {
 	return e.Status() == http.StatusUnprocessableEntity
 }
 
+// Is reports if the error matches target based on the status and error code, see the Err* errors
+func (e *APIError) Is(target error) bool {
+	if isStatusError(e.Status(), target) {
+		return true
+	}
+	codeError, ok := errorCodeErrors[e.ErrorCode()]
+	return ok && codeError == target
+}
+
+// Unwrap returns the underlying error, if any
+func (e *APIError) Unwrap() error {
+	return e.lowerError
+}
+
+// asAPIError returns the api error contained in err or an error it wraps, validation errors embed one
+func asAPIError(err error) (*APIError, bool) {
+	var validationError *APIValidationError
+	if errors.As(err, &validationError) {
+		return &validationError.APIError, true
+	}
+	var apiError *APIError
+	if errors.As(err, &apiError) {
+		return apiError, true
+	}
+	return nil, false
+}
+
 // ClientError struct contains the errors returned when communicating with the server
 type ClientError struct {
 	LowerError error
 	Message    string
 	Code       int
+	RetryAfter time.Duration
+
+	// body is the unexpected response body contained in Message, kept to redact it in the logs
+	body []byte
 }
 
 // Error casts to an actual error struct
@@ -118,16 +238,117 @@ func (e ClientError) Error() string {
 	return fmt.Sprintf("Generic client error [%d:%s]", e.Code, e.Message)
 }
 
+// Is reports if the error matches target based on the status code, see the Err* errors
+func (e ClientError) Is(target error) bool {
+	return isStatusError(e.Code, target)
+}
+
+// Unwrap returns the lower error, if any
+func (e ClientError) Unwrap() error {
+	return e.LowerError
+}
+
 //
 // API Validation Errors
 //
 // On the API side the returned content of validation_messages does not have one structure, can have multiple
-//   the choice was made to either standardize or add parsing code here later on.
+//   the known shapes are normalised into a list of ValidationFieldError, the unstructured json is still available.
+//
+//   {"-": {"<rule>": "<message>"}}                                           => error not bound to a field
+//   {"<field>": {"<rule>": "<message>"}}                                     => field error
+//   {"<field>": "<message>"} or {"<field>": ["<message>"]}                   => field error without a rule
+//   {"<field>": {"<index>": {"value": <value>, "validation_errors": {...}}}} => error for an item of a list field
+//   {"<field>": {"<subfield>": {...}}}                                       => nested field <field>.<subfield>
 //
 
 // ValidationErrors represents the current unstructured json
 type ValidationErrors map[string]interface{}
 
+// ValidationFieldError contains one validation failure as reported by the api
+type ValidationFieldError struct {
+	// Field the path of the field, nested fields and list items are separated by a dot (recipients.1),
+	// empty if the error is not bound to a field
+	Field string
+	// Rule the name of the rule that failed (notDigits, isEmpty, ...), empty if the api did not send one
+	Rule string
+	// Message the human readable message
+	Message string
+	// Value the value that failed validation, if sent by the api
+	Value interface{}
+}
+
+// Error casts to an actual error struct
+func (e ValidationFieldError) Error() string {
+	if e.Field == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// validationNoField is the key used by the api for errors that are not bound to a field
+const validationNoField = "-"
+
+// parseValidationErrors normalises the validation_messages, the result is sorted on field and rule
+func parseValidationErrors(validation ValidationErrors) []ValidationFieldError {
+	var fieldErrors []ValidationFieldError
+	for key, value := range validation {
+		field := key
+		if key == validationNoField {
+			field = ""
+		}
+		fieldErrors = appendValidationErrors(fieldErrors, field, value, nil)
+	}
+
+	sort.SliceStable(fieldErrors, func(i, j int) bool {
+		if fieldErrors[i].Field != fieldErrors[j].Field {
+			return fieldErrors[i].Field < fieldErrors[j].Field
+		}
+		return fieldErrors[i].Rule < fieldErrors[j].Rule
+	})
+
+	return fieldErrors
+}
+
+func appendValidationErrors(
+	fieldErrors []ValidationFieldError,
+	field string,
+	node interface{},
+	value interface{},
+) []ValidationFieldError {
+	switch v := node.(type) {
+	case string:
+		return append(fieldErrors, ValidationFieldError{Field: field, Message: v, Value: value})
+	case []interface{}:
+		for _, item := range v {
+			fieldErrors = appendValidationErrors(fieldErrors, field, item, value)
+		}
+	case map[string]interface{}:
+		if rules, ok := v["validation_errors"]; ok {
+			// list item (or field) with the offending value
+			return appendValidationErrors(fieldErrors, field, rules, v["value"])
+		}
+		for key, item := range v {
+			if message, ok := item.(string); ok {
+				fieldErrors = append(
+					fieldErrors,
+					ValidationFieldError{Field: field, Rule: key, Message: message, Value: value},
+				)
+				continue
+			}
+			fieldErrors = appendValidationErrors(fieldErrors, joinValidationField(field, key), item, value)
+		}
+	}
+
+	return fieldErrors
+}
+
+func joinValidationField(field string, key string) string {
+	if field == "" {
+		return key
+	}
+	return field + "." + key
+}
+
 type jsonAPIValidationError struct {
 	jsonAPIError
 	Validation ValidationErrors `json:"validation_messages"`
@@ -136,7 +357,8 @@ type jsonAPIValidationError struct {
 // APIValidationError struct
 type APIValidationError struct {
 	APIError
-	validation ValidationErrors
+	validation  ValidationErrors
+	fieldErrors []ValidationFieldError
 }
 
 // UnmarshalJSON unmarshals the json returned by the server into the APIErrorResponse struct
@@ -154,11 +376,33 @@ func (e *APIValidationError) UnmarshalJSON(j []byte) error {
 func (e *APIValidationError) copyFrom(j *jsonAPIValidationError) error {
 	err := e.APIError.copyFrom(&j.jsonAPIError)
 	e.validation = j.Validation
+	e.fieldErrors = parseValidationErrors(j.Validation)
 
 	return err
 }
 
 // VerificationErrors returns the unstructured json error struct (interface {})
-func (e APIValidationError) VerificationErrors() ValidationErrors {
+func (e *APIValidationError) VerificationErrors() ValidationErrors {
 	return e.validation
 }
+
+// FieldErrors returns the validation errors, sorted on field and rule
+func (e *APIValidationError) FieldErrors() []ValidationFieldError {
+	return e.fieldErrors
+}
+
+// FieldErrorsFor returns the validation errors of field, including those of its items and nested fields
+func (e *APIValidationError) FieldErrorsFor(field string) []ValidationFieldError {
+	var fieldErrors []ValidationFieldError
+	for _, fieldError := range e.fieldErrors {
+		if fieldError.Field == field || strings.HasPrefix(fieldError.Field, field+".") {
+			fieldErrors = append(fieldErrors, fieldError)
+		}
+	}
+	return fieldErrors
+}
+
+// Is reports if the error matches target, a validation error always matches ErrValidation
+func (e *APIValidationError) Is(target error) bool {
+	return target == ErrValidation || e.APIError.Is(target)
+}
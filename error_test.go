@@ -4,12 +4,16 @@ package twizo_test
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"testing"
 
 	"net/http"
 
 	twizo "github.com/twizoapi/lib-api-go"
 	. "github.com/twizoapi/lib-api-go/testing"
+
+	"gopkg.in/jarcoal/httpmock.v1"
 )
 
 func init() {
@@ -198,4 +202,201 @@ func TestAPIErrorVerificationFieldResponse(t *testing.T) {
 			apiError.Detail(),
 		)
 	}
+	if apiError.ErrorCode() != 2 {
+		t.Fatalf("Invalid error code expecting [2] got [%d]", apiError.ErrorCode())
+	}
+	if apiError.Type() != "http://www.w3.org/Protocols/rfc2616/rfc2616-sec10.html" {
+		t.Fatalf(
+			"Invalid type expecting [http://www.w3.org/Protocols/rfc2616/rfc2616-sec10.html] got [%v]",
+			apiError.Type(),
+		)
+	}
+}
+
+func TestAPIErrorIs(t *testing.T) {
+	tests := []struct {
+		status    int
+		errorCode int
+		expect    []error
+	}{
+		{http.StatusBadRequest, 0, []error{twizo.ErrBadRequest}},
+		{http.StatusUnauthorized, 0, []error{twizo.ErrUnauthorized}},
+		{http.StatusNotFound, 0, []error{twizo.ErrNotFound}},
+		{http.StatusConflict, 0, []error{twizo.ErrConflict}},
+		{http.StatusTooManyRequests, 0, []error{twizo.ErrRateLimited}},
+		{http.StatusServiceUnavailable, 0, []error{twizo.ErrServer}},
+		{http.StatusUnprocessableEntity, 103, []error{twizo.ErrValidation, twizo.ErrTokenInvalid}},
+		{http.StatusLocked, 102, []error{twizo.ErrLocked, twizo.ErrTokenExpired}},
+		{http.StatusInternalServerError, 1, []error{twizo.ErrServer, twizo.ErrInternal}},
+		{http.StatusBadRequest, 2, []error{twizo.ErrBadRequest, twizo.ErrValidation}},
+		{http.StatusForbidden, 3, []error{twizo.ErrForbidden, twizo.ErrInsufficientBalance}},
+		{http.StatusUnprocessableEntity, 4, []error{twizo.ErrValidation, twizo.ErrRecipientBlocked}},
+		{http.StatusUnprocessableEntity, 5, []error{twizo.ErrValidation, twizo.ErrUnsupportedRecipient}},
+	}
+
+	for _, test := range tests {
+		apiError := &twizo.APIError{}
+		err := json.Unmarshal(
+			[]byte(fmt.Sprintf(`{"status":%d,"errorCode":%d}`, test.status, test.errorCode)),
+			apiError,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// wrapping should not matter
+		err = fmt.Errorf("wrapped: %w", apiError)
+
+		for _, expect := range test.expect {
+			if !errors.Is(err, expect) {
+				t.Fatalf("Expecting [%d:%d] to be [%v]", test.status, test.errorCode, expect)
+			}
+		}
+		if errors.Is(err, twizo.ErrTokenAlreadyVerified) {
+			t.Fatalf("Expecting [%d:%d] not to be [%v]", test.status, test.errorCode, twizo.ErrTokenAlreadyVerified)
+		}
+
+		var asAPIError *twizo.APIError
+		if !errors.As(err, &asAPIError) || asAPIError.Status() != test.status {
+			t.Fatalf("Expecting errors.As to find the api error for [%d:%d]", test.status, test.errorCode)
+		}
+	}
+}
+
+func TestClientErrorIs(t *testing.T) {
+	lowerError := errors.New("lower")
+	err := error(&twizo.ClientError{LowerError: lowerError, Code: http.StatusNotFound})
+
+	if !errors.Is(err, twizo.ErrNotFound) {
+		t.Fatalf("Expecting [%v] to be [%v]", err, twizo.ErrNotFound)
+	}
+	if !errors.Is(err, lowerError) {
+		t.Fatalf("Expecting [%v] to unwrap to [%v]", err, lowerError)
+	}
+	if errors.Is(err, twizo.ErrConflict) {
+		t.Fatalf("Expecting [%v] not to be [%v]", err, twizo.ErrConflict)
+	}
+}
+
+func TestErrorIsFromServer(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	HTTPMockSend(
+		http.MethodGet,
+		fmt.Sprintf("https://%s/%s/sms/submit/messageId", twizo.GetHostForRegion(TestRegion), twizo.ClientAPIVersion),
+		http.StatusNotFound,
+		twizo.NewAPIError("Not Found", http.StatusNotFound),
+		nil,
+	)
+
+	response, err := twizo.SmsStatus("messageId")
+	if !errors.Is(err, twizo.ErrNotFound) {
+		t.Fatalf("Invalid error expecting [%v] got [%#v]", twizo.ErrNotFound, err)
+	}
+	if response != nil {
+		t.Fatalf("Invalid response expecting [nil] got [%#v]", response)
+	}
+
+	HTTPMockSend(
+		http.MethodGet,
+		fmt.Sprintf(
+			"https://%s/%s/numberlookup/submit/messageId",
+			twizo.GetHostForRegion(TestRegion),
+			twizo.ClientAPIVersion,
+		),
+		http.StatusNotFound,
+		twizo.NewAPIError("Not Found", http.StatusNotFound),
+		nil,
+	)
+
+	_, err = twizo.NumberLookupStatus("messageId")
+	if !errors.Is(err, twizo.ErrNotFound) {
+		t.Fatalf("Invalid error expecting [%v] got [%#v]", twizo.ErrNotFound, err)
+	}
+
+	// validation errors are returned as APIValidationError
+	httpmock.RegisterResponder(
+		http.MethodGet,
+		fmt.Sprintf("https://%s/%s/sms/submit/messageId", twizo.GetHostForRegion(TestRegion), twizo.ClientAPIVersion),
+		newProblemResponder(http.StatusUnprocessableEntity, 0),
+	)
+
+	_, err = twizo.SmsStatus("messageId")
+	var validationError *twizo.APIValidationError
+	if !errors.As(err, &validationError) || !errors.Is(err, twizo.ErrValidation) {
+		t.Fatalf("Invalid error expecting [twizo.APIValidationError] got [%#v]", err)
+	}
+}
+
+func TestAPIValidationErrorFieldErrors(t *testing.T) {
+	cannedResponse := `{
+		"validation_messages": {
+			"-": {
+				"invalidFields":"The following field(s) are not allowed: 'test'"
+			},
+			"recipients": {
+				"1": {
+					"value":"g",
+					"validation_errors": {
+						"stringLengthTooShort":"The input is less than 8 characters long",
+						"notDigits":"The input must contain only digits"
+					}
+				}
+			},
+			"sender": {
+				"isEmpty":"Value is required and can't be empty"
+			},
+			"body": "Value is required",
+			"tag": ["Tag is too long"],
+			"webHook": {
+				"url": {
+					"uriInvalid":"The input does not appear to be a valid Uri"
+				}
+			}
+		},
+		"type":"http://www.w3.org/Protocols/rfc2616/rfc2616-sec10.html",
+		"title":"Unprocessable Entity",
+		"status":422,
+		"detail":"Failed Validation"
+	}`
+	apiError := &twizo.APIValidationError{}
+	err := json.Unmarshal([]byte(cannedResponse), apiError)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []twizo.ValidationFieldError{
+		{Field: "", Rule: "invalidFields", Message: "The following field(s) are not allowed: 'test'"},
+		{Field: "body", Rule: "", Message: "Value is required"},
+		{Field: "recipients.1", Rule: "notDigits", Message: "The input must contain only digits", Value: "g"},
+		{
+			Field:   "recipients.1",
+			Rule:    "stringLengthTooShort",
+			Message: "The input is less than 8 characters long",
+			Value:   "g",
+		},
+		{Field: "sender", Rule: "isEmpty", Message: "Value is required and can't be empty"},
+		{Field: "tag", Rule: "", Message: "Tag is too long"},
+		{Field: "webHook.url", Rule: "uriInvalid", Message: "The input does not appear to be a valid Uri"},
+	}
+
+	fieldErrors := apiError.FieldErrors()
+	if len(fieldErrors) != len(expect) {
+		t.Fatalf("Invalid amount of field errors expecting [%d] got [%#v]", len(expect), fieldErrors)
+	}
+	for i := range expect {
+		if fieldErrors[i] != expect[i] {
+			t.Fatalf("Invalid field error [%d] expecting [%#v] got [%#v]", i, expect[i], fieldErrors[i])
+		}
+	}
+
+	if recipientErrors := apiError.FieldErrorsFor("recipients"); len(recipientErrors) != 2 {
+		t.Fatalf("Invalid amount of recipient errors expecting [2] got [%d]", len(recipientErrors))
+	}
+	if fieldErrors[2].Error() != "recipients.1: The input must contain only digits" {
+		t.Fatalf(
+			"Invalid error expecting [recipients.1: The input must contain only digits] got [%s]",
+			fieldErrors[2].Error(),
+		)
+	}
 }
@@ -19,10 +19,13 @@ func main() {
 	//
 	phone, _ := utils.AskForInput("Enter phone number [6100000000]: ", "6100000000")
 
-	numberLookupRequest := twizo.NewNumberLookupRequest([]twizo.Recipient{twizo.Recipient(phone)})
+	numberLookupRequest, err := twizo.NewNumberLookupRequest([]twizo.Recipient{twizo.Recipient(phone)})
+	if err != nil {
+		panic(err)
+	}
 	numberLookupRequest.SetResultType(twizo.ResultTypePolling)
 
-	_, err := numberLookupRequest.Submit()
+	_, err = numberLookupRequest.Submit()
 	if err != nil {
 		panic(err)
 	}
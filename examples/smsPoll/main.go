@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	twizo "github.com/twizoapi/lib-api-go"
 	"github.com/twizoapi/lib-api-go/examples"
@@ -35,30 +36,20 @@ func main() {
 	}
 
 	// now we can poll for the results, keep in mind all poll results will be retrieved not only
-	// our sent message
-	maxPolls := 10
-	for i := 1; i <= maxPolls; i++ {
-		fmt.Printf("Polling [%d/%d]\n", i, maxPolls)
-		smsPollResults, err := twizo.SmsPollStatus()
-		if err != nil {
-			panic(err)
-		}
-
-		//fmt.Print(utils.GetJsonFor(smsPollResults))
-		for _, smsResponse := range smsPollResults.GetItems() {
-			fmt.Printf(
-				"- Sms to [%s] has status [%s]\n",
-				smsResponse.GetRecipient(),
-				smsResponse.GetStatusMsg(),
-			)
-		}
-		// delete the poll result we processed it above
-		err = smsPollResults.Delete()
-		if err != nil {
-			// failed to delete
-			panic(err)
-		}
-
-		time.Sleep(2 * time.Second)
+	// our sent message. The poller deletes a batch once all its messages were handled.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	poller := twizo.NewSmsPoller(func(ctx context.Context, smsResponse *twizo.SmsResponse) error {
+		fmt.Printf(
+			"- Sms to [%s] has status [%s]\n",
+			smsResponse.GetRecipient(),
+			smsResponse.GetStatusMsg(),
+		)
+		return nil
+	})
+	err = poller.Run(ctx)
+	if err != nil {
+		panic(err)
 	}
 }
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"log"
 	"os"
 	"reflect"
 	"regexp"
@@ -76,7 +77,7 @@ func Main() {
 	SuppliedRegion = *region
 
 	if *isVerbose {
-		twizo.DebugLogger.SetOutput(os.Stdout)
+		twizo.DefaultLogger = twizo.NewLogLogger(log.New(os.Stdout, "DEBUG: ", log.Ldate|log.Ltime))
 	}
 }
 
@@ -0,0 +1,162 @@
+package twizo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Logger is the structured logger used to log every call, the args are
+// alternating keys and values. A *slog.Logger implements it.
+type Logger interface {
+	DebugContext(ctx context.Context, msg string, args ...interface{})
+}
+
+// DefaultLogger is used by clients without a Logger, nothing is logged when nil
+var DefaultLogger Logger
+
+// redacted replaces sensitive values in the logs
+const redacted = "[REDACTED]"
+
+// sensitiveFields contains the json fields and query parameters that are
+// redacted: tokens, backup codes, totp secrets and phone numbers
+var sensitiveFields = map[string]bool{
+	"token":        true,
+	"sessionToken": true,
+	"codes":        true,
+	"secret":       true,
+	"uri":          true, // the otpauth uri of a totp contains the secret
+	"recipient":    true,
+	"recipients":   true,
+	"number":       true,
+	"numbers":      true,
+	"imsi":         true,
+}
+
+// phoneNumberPath matches path segments that look like a phone number
+var phoneNumberPath = regexp.MustCompile(`^\+?[0-9]{6,}$`)
+
+type logLogger struct {
+	logger *log.Logger
+}
+
+// NewLogLogger returns a Logger writing key=value lines to logger, for those not using log/slog
+func NewLogLogger(logger *log.Logger) Logger {
+	return &logLogger{logger: logger}
+}
+
+// DebugContext logs msg followed by the key value pairs in args
+func (l *logLogger) DebugContext(ctx context.Context, msg string, args ...interface{}) {
+	var b strings.Builder
+	b.WriteString(msg)
+	for i := 0; i < len(args); i += 2 {
+		if i+1 < len(args) {
+			fmt.Fprintf(&b, " %v=%q", args[i], fmt.Sprint(args[i+1]))
+		} else {
+			fmt.Fprintf(&b, " %q", fmt.Sprint(args[i]))
+		}
+	}
+	l.logger.Print(b.String())
+}
+
+func (c *Client) getLogger() Logger {
+	if c.Logger != nil {
+		return c.Logger
+	}
+	return DefaultLogger
+}
+
+// logURL returns u for logging, with the sensitive parts redacted unless the client opted in
+func (c *Client) logURL(u *url.URL) string {
+	if c.LogSensitiveData || u == nil {
+		return fmt.Sprint(u)
+	}
+
+	clean := *u
+	segments := strings.Split(clean.Path, "/")
+	for i, segment := range segments {
+		if phoneNumberPath.MatchString(segment) {
+			segments[i] = redacted
+		}
+	}
+	clean.Path = strings.Join(segments, "/")
+	clean.RawPath = ""
+
+	q := clean.Query()
+	for key := range q {
+		if sensitiveFields[key] {
+			q.Set(key, redacted)
+		}
+	}
+	clean.RawQuery = q.Encode()
+
+	return clean.String()
+}
+
+// logBody returns body for logging, with the sensitive json fields redacted unless the client opted in
+func (c *Client) logBody(body []byte) string {
+	if c.LogSensitiveData {
+		return string(body)
+	}
+
+	var v interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&v); err != nil {
+		// not json, nothing we know how to redact
+		return string(body)
+	}
+
+	clean, err := json.Marshal(redactJSON(v))
+	if err != nil {
+		return redacted
+	}
+	return string(clean)
+}
+
+// logError returns err for logging, url errors contain the url with the query and client errors the
+// response body
+func (c *Client) logError(err error) string {
+	if c.LogSensitiveData {
+		return err.Error()
+	}
+
+	switch e := err.(type) {
+	case *url.Error:
+		u, parseErr := url.Parse(e.URL)
+		if parseErr != nil {
+			return fmt.Sprintf("%s: %v", e.Op, e.Err)
+		}
+		return fmt.Sprintf("%s %s: %v", e.Op, c.logURL(u), e.Err)
+	case *ClientError:
+		if e.body != nil {
+			clean := *e
+			clean.Message = fmt.Sprintf("Unexpected response [%s]", c.logBody(e.body))
+			return clean.Error()
+		}
+	}
+	return err.Error()
+}
+
+func redactJSON(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for key, item := range value {
+			if sensitiveFields[key] && item != nil {
+				value[key] = redacted
+				continue
+			}
+			value[key] = redactJSON(item)
+		}
+	case []interface{}:
+		for i, item := range value {
+			value[i] = redactJSON(item)
+		}
+	}
+	return v
+}
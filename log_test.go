@@ -0,0 +1,196 @@
+package twizo_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	twizo "github.com/twizoapi/lib-api-go"
+	. "github.com/twizoapi/lib-api-go/testing"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+func init() {
+	twizo.APIKey = TestAPIKey
+	twizo.RegionCurrent = TestRegion
+}
+
+type logEntry struct {
+	msg    string
+	fields map[string]string
+}
+
+type recordingLogger struct {
+	entries []logEntry
+}
+
+func (l *recordingLogger) DebugContext(ctx context.Context, msg string, args ...interface{}) {
+	entry := logEntry{msg: msg, fields: map[string]string{}}
+	for i := 0; i+1 < len(args); i += 2 {
+		entry.fields[fmt.Sprint(args[i])] = fmt.Sprint(args[i+1])
+	}
+	l.entries = append(l.entries, entry)
+}
+
+func (l *recordingLogger) all() string {
+	return fmt.Sprint(l.entries)
+}
+
+func TestLoggerRedactsBackupCodes(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	HTTPMockSend(
+		http.MethodPost,
+		fmt.Sprintf("https://%s/%s/backupcode", twizo.GetHostForRegion(TestRegion), twizo.ClientAPIVersion),
+		http.StatusCreated,
+		`{"identifier":"identifier","amountOfCodesLeft":1,"codes":["8748327643"]}`,
+		nil,
+	)
+
+	logger := &recordingLogger{}
+	client := twizo.NewClient(TestAPIKey, TestRegion)
+	client.Logger = logger
+
+	if _, err := client.BackupCodeCreate("identifier"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(logger.entries) != 2 {
+		t.Fatalf("Invalid amount of log entries expecting [2] got [%d] %s", len(logger.entries), logger.all())
+	}
+
+	response := logger.entries[1]
+	if response.msg != "twizo response" {
+		t.Fatalf("Invalid message expecting [twizo response] got [%s]", response.msg)
+	}
+	if response.fields["endpoint"] != string(twizo.EndpointBackupCodeCreate) {
+		t.Fatalf("Invalid endpoint expecting [%s] got [%s]", twizo.EndpointBackupCodeCreate, response.fields["endpoint"])
+	}
+	if response.fields["status"] != "201" {
+		t.Fatalf("Invalid status expecting [201] got [%s]", response.fields["status"])
+	}
+	if strings.Contains(logger.all(), "8748327643") {
+		t.Fatalf("Backup codes should be redacted got %s", logger.all())
+	}
+}
+
+func TestLoggerRedactsTokenAndRecipient(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	verificationURL := fmt.Sprintf(
+		"https://%s/%s/verification/submit/messageId",
+		twizo.GetHostForRegion(TestRegion),
+		twizo.ClientAPIVersion,
+	)
+
+	for _, sensitive := range []bool{false, true} {
+		// mocked responses can only be read once
+		HTTPMockSend(
+			http.MethodGet,
+			verificationURL,
+			http.StatusOK,
+			fmt.Sprintf(
+				`{"messageId":"messageId","recipient":"31600000000","_links":{"self":{"href":"%s"}}}`,
+				verificationURL,
+			),
+			nil,
+		)
+		HTTPMockSend(
+			http.MethodGet,
+			verificationURL+"?token=918273",
+			http.StatusOK,
+			`{"messageId":"messageId","recipient":"31600000000","statusCode":1}`,
+			nil,
+		)
+
+		logger := &recordingLogger{}
+		client := twizo.NewClient(TestAPIKey, TestRegion)
+		client.Logger = logger
+		client.LogSensitiveData = sensitive
+
+		response, err := client.VerificationStatus("messageId")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := response.Verify("918273"); err != nil {
+			t.Fatal(err)
+		}
+
+		last := logger.entries[len(logger.entries)-1]
+		if last.fields["message_id"] != "messageId" {
+			t.Fatalf("Invalid message id expecting [messageId] got [%s]", last.fields["message_id"])
+		}
+
+		logged := logger.all()
+		for _, secret := range []string{"918273", "31600000000"} {
+			if strings.Contains(logged, secret) != sensitive {
+				t.Fatalf("Expecting [%s] to be logged [%v] got %s", secret, sensitive, logged)
+			}
+		}
+	}
+}
+
+func TestLoggerRedactsErrors(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	// an unexpected response is returned as client error containing the body
+	httpmock.RegisterResponder(
+		http.MethodGet,
+		fmt.Sprintf(
+			"https://%s/%s/verification/submit/messageId",
+			twizo.GetHostForRegion(TestRegion),
+			twizo.ClientAPIVersion,
+		),
+		func(req *http.Request) (*http.Response, error) {
+			return httpmock.NewStringResponse(
+				http.StatusServiceUnavailable,
+				`{"messageId":"messageId","recipient":"31600000000"}`,
+			), nil
+		},
+	)
+
+	logger := &recordingLogger{}
+	client := twizo.NewClient(TestAPIKey, TestRegion)
+	client.Logger = logger
+	client.RetryPolicy = &twizo.RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond}
+
+	_, err := client.VerificationStatus("messageId")
+	if err == nil || !strings.Contains(err.Error(), "31600000000") {
+		t.Fatalf("Expecting error containing the response body got [%v]", err)
+	}
+
+	var retry *logEntry
+	for i := range logger.entries {
+		if logger.entries[i].msg == "twizo retry" {
+			retry = &logger.entries[i]
+		}
+	}
+	if retry == nil {
+		t.Fatalf("Expecting [twizo retry] to be logged got %s", logger.all())
+	}
+	if !strings.Contains(retry.fields["error"], "503") {
+		t.Fatalf("Invalid error expecting the status code got [%s]", retry.fields["error"])
+	}
+	if strings.Contains(logger.all(), "31600000000") {
+		t.Fatalf("Recipient should be redacted got %s", logger.all())
+	}
+}
+
+func TestNewLogLogger(t *testing.T) {
+	var b bytes.Buffer
+	logger := twizo.NewLogLogger(log.New(&b, "", 0))
+	logger.DebugContext(context.Background(), "twizo response", "status", 200, "endpoint", twizo.EndpointSmsSubmit)
+
+	if b.String() != "twizo response status=\"200\" endpoint=\"sms.submit\"\n" {
+		t.Fatalf("Invalid log line got [%s]", b.String())
+	}
+}
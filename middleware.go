@@ -0,0 +1,105 @@
+package twizo
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Endpoint is the name of an api operation, it is passed to the middleware so
+// calls can be told apart without parsing urls
+type Endpoint string
+
+// All endpoints called by the library
+const (
+	EndpointUnknown                         Endpoint = ""
+	EndpointApplicationVerifyCredentials    Endpoint = "application.verifycredentials"
+	EndpointBackupCodeCreate                Endpoint = "backupcode.create"
+	EndpointBackupCodeUpdate                Endpoint = "backupcode.update"
+	EndpointBackupCodeVerify                Endpoint = "backupcode.verify"
+	EndpointBackupCodeStatus                Endpoint = "backupcode.status"
+	EndpointBackupCodeDelete                Endpoint = "backupcode.delete"
+	EndpointBalanceGet                      Endpoint = "balance.get"
+	EndpointBioVoiceCreateRegistration      Endpoint = "biovoice.registration.create"
+	EndpointBioVoiceCheckRegistration       Endpoint = "biovoice.registration.check"
+	EndpointBioVoiceCheckSubscription       Endpoint = "biovoice.subscription.check"
+	EndpointBioVoiceDeleteSubscription      Endpoint = "biovoice.subscription.delete"
+	EndpointNumberLookupSubmit              Endpoint = "numberlookup.submit"
+	EndpointNumberLookupStatus              Endpoint = "numberlookup.status"
+	EndpointNumberLookupPoll                Endpoint = "numberlookup.poll"
+	EndpointNumberLookupPollDelete          Endpoint = "numberlookup.poll.delete"
+	EndpointRegistrationWidgetSessionSubmit Endpoint = "registrationwidgetsession.submit"
+	EndpointSmsSubmit                       Endpoint = "sms.submit"
+	EndpointSmsStatus                       Endpoint = "sms.status"
+	EndpointSmsPoll                         Endpoint = "sms.poll"
+	EndpointSmsPollDelete                   Endpoint = "sms.poll.delete"
+	EndpointTotpCreate                      Endpoint = "totp.create"
+	EndpointTotpVerify                      Endpoint = "totp.verify"
+	EndpointTotpCheck                       Endpoint = "totp.check"
+	EndpointTotpDelete                      Endpoint = "totp.delete"
+	EndpointVerificationSubmit              Endpoint = "verification.submit"
+	EndpointVerificationStatus              Endpoint = "verification.status"
+	EndpointVerificationVerify              Endpoint = "verification.verify"
+	EndpointVerificationTypes               Endpoint = "verification.types"
+	EndpointWidgetSessionSubmit             Endpoint = "widgetsession.submit"
+	EndpointWidgetSessionStatus             Endpoint = "widgetsession.status"
+	EndpointWidgetSessionVerify             Endpoint = "widgetsession.verify"
+)
+
+// CallInfo describes one attempt of an api call, middleware can change the
+// request before passing it on and inspect the outcome afterwards
+type CallInfo struct {
+	// Endpoint the operation being called, EndpointUnknown when using Call directly
+	Endpoint Endpoint
+
+	// Region the region of the client making the call
+	Region APIRegion
+
+	// Request the http request about to be sent, headers can be added or changed
+	Request *http.Request
+
+	// RequestBody the marshalled request body, nil if there is none
+	RequestBody []byte
+
+	// ExpectCode the status code expected on success
+	ExpectCode int
+
+	// Attempt the attempt number, starts at 1 and is increased when retrying
+	Attempt int
+
+	// StatusCode the status code of the response, 0 if no response was received
+	StatusCode int
+
+	// ResponseBody the raw response body, nil if no response was received
+	ResponseBody []byte
+
+	// Result the value the response is decoded into, nil if no result is expected.
+	// Middleware that answers a call itself (for example from a cache) should
+	// decode into Result and not call next
+	Result interface{}
+}
+
+// MessageID returns the message id from the response body, empty if there is none
+func (info *CallInfo) MessageID() string {
+	var response struct {
+		MessageID string `json:"messageId"`
+	}
+	if err := json.Unmarshal(info.ResponseBody, &response); err != nil {
+		return ""
+	}
+	return response.MessageID
+}
+
+// CallHandler performs the call described by info
+type CallHandler func(info *CallInfo) error
+
+// Middleware wraps the handler of a call, it is called for every attempt
+type Middleware func(next CallHandler) CallHandler
+
+// handler returns the middleware chain of the client around handler, the first
+// middleware is the outermost
+func (c *Client) handler(handler CallHandler) CallHandler {
+	for i := len(c.Middleware) - 1; i >= 0; i-- {
+		handler = c.Middleware[i](handler)
+	}
+	return handler
+}
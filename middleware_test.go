@@ -0,0 +1,109 @@
+package twizo_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	twizo "github.com/twizoapi/lib-api-go"
+	. "github.com/twizoapi/lib-api-go/testing"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+func init() {
+	twizo.APIKey = TestAPIKey
+	twizo.RegionCurrent = TestRegion
+}
+
+func TestMiddleware(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	HTTPMockSend(
+		http.MethodPost,
+		fmt.Sprintf("https://%s/%s/verification/submit", twizo.GetHostForRegion(TestRegion), twizo.ClientAPIVersion),
+		http.StatusCreated,
+		`{"messageId":"messageId"}`,
+		func(req *http.Request) error {
+			if req.Header.Get("X-Trace") != "trace" {
+				return fmt.Errorf("invalid trace header expecting [trace] got [%s]", req.Header.Get("X-Trace"))
+			}
+			return nil
+		},
+	)
+
+	var order []string
+	var seen twizo.CallInfo
+	client := twizo.NewClient(TestAPIKey, TestRegion)
+	client.Middleware = []twizo.Middleware{
+		func(next twizo.CallHandler) twizo.CallHandler {
+			return func(info *twizo.CallInfo) error {
+				order = append(order, "outer")
+				info.Request.Header.Set("X-Trace", "trace")
+				err := next(info)
+				seen = *info
+				return err
+			}
+		},
+		func(next twizo.CallHandler) twizo.CallHandler {
+			return func(info *twizo.CallInfo) error {
+				order = append(order, "inner")
+				return next(info)
+			}
+		},
+	}
+
+	response, err := client.VerificationSubmit("31600000000")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fmt.Sprint(order) != "[outer inner]" {
+		t.Fatalf("Invalid middleware order expecting [[outer inner]] got [%v]", order)
+	}
+	if seen.Endpoint != twizo.EndpointVerificationSubmit {
+		t.Fatalf("Invalid endpoint expecting [%s] got [%s]", twizo.EndpointVerificationSubmit, seen.Endpoint)
+	}
+	if seen.StatusCode != http.StatusCreated {
+		t.Fatalf("Invalid status code expecting [%d] got [%d]", http.StatusCreated, seen.StatusCode)
+	}
+	if seen.Result != response {
+		t.Fatalf("Invalid result expecting [%#v] got [%#v]", response, seen.Result)
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(seen.RequestBody, &body); err != nil {
+		t.Fatal(err)
+	}
+	if body["recipient"] != "31600000000" {
+		t.Fatalf("Invalid request body recipient expecting [31600000000] got [%v]", body["recipient"])
+	}
+}
+
+func TestMiddlewareAnswersCall(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	// nothing registered, any call reaching the transport fails
+	client := twizo.NewClient(TestAPIKey, TestRegion)
+	client.Middleware = []twizo.Middleware{
+		func(next twizo.CallHandler) twizo.CallHandler {
+			return func(info *twizo.CallInfo) error {
+				if info.Endpoint != twizo.EndpointBalanceGet {
+					return next(info)
+				}
+				return json.Unmarshal([]byte(`{"wallet":"cached"}`), info.Result)
+			}
+		},
+	}
+
+	response, err := client.BalanceGet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.GetWallet() != "cached" {
+		t.Fatalf("Invalid wallet expecting [cached] got [%s]", response.GetWallet())
+	}
+}
@@ -1,7 +1,9 @@
 package twizo
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -25,13 +27,39 @@ const (
 	NumberLookupStatusCodeUnknown     NumberLookupStatusCode = 9
 )
 
+// IsFinal returns true when the status of the numberlookup will not change anymore
+func (code NumberLookupStatusCode) IsFinal() bool {
+	switch code {
+	case NumberLookupStatusCodeDelivered,
+		NumberLookupStatusCodeRejected,
+		NumberLookupStatusCodeExpired,
+		NumberLookupStatusCodeUndelivered,
+		NumberLookupStatusCodeDeleted:
+		return true
+	}
+	return false
+}
+
+// DefaultNumberLookupInterval is the default interval between the status lookups while waiting for the
+// results of numberlookups
+const DefaultNumberLookupInterval = 2 * time.Second
+
+const (
+	// NumberLookupValidityMin is the minimum validity of a numberlookup
+	NumberLookupValidityMin = 1 * time.Second
+
+	// NumberLookupValidityMax is the maximum validity of a numberlookup
+	NumberLookupValidityMax = 72 * time.Hour
+)
+
 // NumberLookupRequest struct
 type NumberLookupRequest struct {
 	numbers     []Recipient
 	tag         string
-	validity    int
+	validity    time.Duration
 	resultType  ResultType
 	callbackURL *url.URL // only relevant for SmsResultTypeCallback | SmsResultTYpeCallbackPollling
+	client      *Client
 }
 
 type jsonNumberLookupRequest struct {
@@ -39,18 +67,18 @@ type jsonNumberLookupRequest struct {
 	Tag         string      `json:"tag,omitempty"`
 	Validity    int         `json:"validity,omitempty"`
 	ResultType  ResultType  `json:"resultType,omitempty"`
-	CallbackURL *url.URL    `json:"callbackUrl,omitempty"`
+	CallbackURL string      `json:"callbackUrl,omitempty"`
 }
 
 func (request *jsonNumberLookupRequest) copyFrom(r *NumberLookupRequest) {
 	request.Numbers = r.numbers
 	request.Tag = r.tag
-	request.Validity = r.validity
+	request.Validity = int(r.validity / time.Second)
 	request.ResultType = r.resultType
 
 	// set the callback url if we need one, it still might be empty
-	if r.resultType == ResultTypeCallback || r.resultType == ResultTypeCallbackPolling {
-		request.CallbackURL = r.callbackURL
+	if (r.resultType == ResultTypeCallback || r.resultType == ResultTypeCallbackPolling) && r.callbackURL != nil {
+		request.CallbackURL = r.callbackURL.String()
 	}
 }
 
@@ -61,9 +89,15 @@ func (request *NumberLookupRequest) MarshalJSON() ([]byte, error) {
 	return json.Marshal(jsonRequest)
 }
 
-// SetNumbers sets the numbers for a numberlookup request
-func (request *NumberLookupRequest) SetNumbers(numbers []Recipient) {
+// SetNumbers sets the numbers for a numberlookup request, they are normalised using ParseRecipient
+func (request *NumberLookupRequest) SetNumbers(numbers []Recipient) error {
+	numbers, err := ParseRecipients(numbers, clientOrDefault(request.client).getDefaultCountry())
+	if err != nil {
+		return err
+	}
 	request.numbers = numbers
+
+	return nil
 }
 
 // GetNumbers gets the numbers of a numberlookup request
@@ -81,16 +115,31 @@ func (request NumberLookupRequest) GetTag() string {
 	return request.tag
 }
 
-// SetValidity sets the validity for a numberlookup request
-func (request *NumberLookupRequest) SetValidity(validity int) {
+// SetValidity sets the validity for a numberlookup request, it should be whole seconds between
+// NumberLookupValidityMin and NumberLookupValidityMax, 0 uses the default of the api
+func (request *NumberLookupRequest) SetValidity(validity time.Duration) error {
+	if validity != 0 {
+		if err := validateValidity(validity, NumberLookupValidityMin, NumberLookupValidityMax); err != nil {
+			return err
+		}
+	}
 	request.validity = validity
+
+	return nil
 }
 
-// GetValidation returns the validity of a numberlookup request
-func (request NumberLookupRequest) GetValidation() int {
+// GetValidity returns the validity of a numberlookup request, 0 when not set
+func (request NumberLookupRequest) GetValidity() time.Duration {
 	return request.validity
 }
 
+// GetValidation returns the validity of a numberlookup request in seconds
+//
+// Deprecated: use GetValidity
+func (request NumberLookupRequest) GetValidation() int {
+	return int(request.validity / time.Second)
+}
+
 // SetResultType sets the result type for a numberlookup request
 func (request *NumberLookupRequest) SetResultType(resultType ResultType) {
 	request.resultType = resultType
@@ -113,6 +162,11 @@ func (request NumberLookupRequest) GetCallbackURL() *url.URL {
 
 // Submit actually submits the numberlookup request
 func (request *NumberLookupRequest) Submit() (*NumberLookupResponses, error) {
+	return request.SubmitContext(context.Background())
+}
+
+// SubmitContext submits the numberlookup request using ctx for the call
+func (request *NumberLookupRequest) SubmitContext(ctx context.Context) (*NumberLookupResponses, error) {
 	responses := &NumberLookupResponses{}
 
 	apiURL, err := GetURLFor("numberlookup/submit")
@@ -121,7 +175,10 @@ func (request *NumberLookupRequest) Submit() (*NumberLookupResponses, error) {
 	}
 
 	// todo: we need to clear our dcs and udh here, as they are not valid for simple submit
-	err = GetClient(RegionCurrent, APIKey).Call(
+	client := clientOrDefault(request.client)
+	err = client.call(
+		ctx,
+		EndpointNumberLookupSubmit,
 		http.MethodPost,
 		apiURL,
 		request,
@@ -131,6 +188,7 @@ func (request *NumberLookupRequest) Submit() (*NumberLookupResponses, error) {
 	if err != nil {
 		return nil, err
 	}
+	responses.setClient(client)
 
 	return responses, nil
 }
@@ -139,6 +197,7 @@ func (request *NumberLookupRequest) Submit() (*NumberLookupResponses, error) {
 type NumberLookupResponse struct {
 	applicationTag         string
 	callbackURL            *url.URL
+	countryCode            *string
 	createDateTime         time.Time
 	imsi                   *string
 	ported                 string
@@ -149,7 +208,7 @@ type NumberLookupResponse struct {
 	number                 string
 	operator               *string
 	reasonCode             *int
-	resultTimestamp        string
+	resultTimestamp        *time.Time
 	resultType             int
 	salesPrice             *float32
 	salesPriceCurrencyCode *string
@@ -159,6 +218,7 @@ type NumberLookupResponse struct {
 	validity               int
 	validUntilDateTime     time.Time
 	links                  HATEOASLinks
+	client                 *Client
 }
 
 type jsonNumberLookupResponse struct {
@@ -175,7 +235,7 @@ type jsonNumberLookupResponse struct {
 	Number                 string                 `json:"number"`
 	Operator               *string                `json:"operator"`
 	ReasonCode             *int                   `json:"reasonCode,omitempty"`
-	ResultTimestamp        string                 `json:"resulttimestamp,omitempty"`
+	ResultTimestamp        *string                `json:"resulttimestamp,omitempty"`
 	ResultType             int                    `json:"resultType,omitempty"`
 	SalesPrice             *float32               `json:"salesPrice,omitempty"`
 	SalesPriceCurrencyCode *string                `json:"salesPriceCurrencyCode,omitempty"`
@@ -199,9 +259,48 @@ func (response *NumberLookupResponse) UnmarshalJSON(j []byte) error {
 	return response.copyFrom(jsonResponse)
 }
 
+// MarshalJSON converts the response to json in the format of the api, allowing it to be stored
+func (response *NumberLookupResponse) MarshalJSON() ([]byte, error) {
+	jsonResponse := jsonNumberLookupResponse{
+		ApplicationTag:         response.applicationTag,
+		CountryCode:            response.countryCode,
+		CreateDateTime:         response.createDateTime,
+		Imsi:                   response.imsi,
+		Ported:                 response.ported,
+		Roaming:                response.roaming,
+		MessageID:              response.messageID,
+		Msc:                    response.msc,
+		NetworkCode:            response.networkCode,
+		Number:                 response.number,
+		Operator:               response.operator,
+		ReasonCode:             response.reasonCode,
+		ResultType:             response.resultType,
+		SalesPrice:             response.salesPrice,
+		SalesPriceCurrencyCode: response.salesPriceCurrencyCode,
+		StatusMsg:              response.statusMsg,
+		StatusCode:             response.statusCode,
+		Tag:                    response.tag,
+		Validity:               response.validity,
+		ValidUntilDateTime:     response.validUntilDateTime,
+		Links:                  response.links,
+	}
+
+	if response.callbackURL != nil {
+		callbackURL := response.callbackURL.String()
+		jsonResponse.CallbackURL = &callbackURL
+	}
+	if response.resultTimestamp != nil {
+		resultTimestamp := response.resultTimestamp.Format(time.RFC3339Nano)
+		jsonResponse.ResultTimestamp = &resultTimestamp
+	}
+
+	return json.Marshal(jsonResponse)
+}
+
 func (response *NumberLookupResponse) copyFrom(j *jsonNumberLookupResponse) error {
 
 	response.applicationTag = j.ApplicationTag
+	response.countryCode = j.CountryCode
 	response.createDateTime = j.CreateDateTime
 	response.imsi = j.Imsi
 	response.ported = j.Ported
@@ -212,7 +311,6 @@ func (response *NumberLookupResponse) copyFrom(j *jsonNumberLookupResponse) erro
 	response.number = j.Number
 	response.operator = j.Operator
 	response.reasonCode = j.ReasonCode
-	response.resultTimestamp = j.ResultTimestamp
 	response.resultType = j.ResultType
 	response.salesPrice = j.SalesPrice
 	response.salesPriceCurrencyCode = j.SalesPriceCurrencyCode
@@ -231,24 +329,37 @@ func (response *NumberLookupResponse) copyFrom(j *jsonNumberLookupResponse) erro
 		response.callbackURL = u
 	}
 
+	resultTimestamp, err := parseTimestamp(j.ResultTimestamp)
+	if err != nil {
+		return err
+	}
+	response.resultTimestamp = resultTimestamp
+
 	return nil
 }
 
 // Status requests the status of a numberlookup
 func (response *NumberLookupResponse) Status() error {
-	newNumberLookupResponse := &NumberLookupResponse{}
+	return response.StatusContext(context.Background())
+}
+
+// StatusContext requests the status of a numberlookup using ctx for the call
+func (response *NumberLookupResponse) StatusContext(ctx context.Context) error {
+	newNumberLookupResponse := &NumberLookupResponse{client: response.client}
 
-	err := GetClient(RegionCurrent, APIKey).Call(
+	err := clientOrDefault(response.client).call(
+		ctx,
+		EndpointNumberLookupStatus,
 		http.MethodGet,
 		&response.links.Self.Href,
 		nil,
 		http.StatusOK,
-		response,
+		newNumberLookupResponse,
 	)
 
 	if err == nil {
 		// no error use response to override ourselves
-		*newNumberLookupResponse = *response
+		*response = *newNumberLookupResponse
 	}
 
 	return err
@@ -274,12 +385,17 @@ func (response NumberLookupResponse) GetImsi() *string {
 	return response.imsi
 }
 
-// GetPorted gets ported of the response
+// GetCountryCode gets the country code of the response, see GetCountry for the derived country
+func (response NumberLookupResponse) GetCountryCode() *string {
+	return response.countryCode
+}
+
+// GetPorted gets ported of the response, see GetPortedStatus for the typed value
 func (response NumberLookupResponse) GetPorted() string {
 	return response.ported
 }
 
-// GetRoaming gets roaming of the response
+// GetRoaming gets roaming of the response, see GetRoamingStatus for the typed value
 func (response NumberLookupResponse) GetRoaming() string {
 	return response.roaming
 }
@@ -344,11 +460,21 @@ func (response NumberLookupResponse) GetTag() *string {
 	return response.tag
 }
 
-// GetResultTimeStamp of the response
-func (response NumberLookupResponse) GetResultTimeStamp() string {
+// GetResultTimestamp returns the timestamp of the result, nil when there is no result yet
+func (response NumberLookupResponse) GetResultTimestamp() *time.Time {
 	return response.resultTimestamp
 }
 
+// GetResultTimeStamp of the response in ISO-8601 format, empty when there is no result yet
+//
+// Deprecated: use GetResultTimestamp
+func (response NumberLookupResponse) GetResultTimeStamp() string {
+	if response.resultTimestamp == nil {
+		return ""
+	}
+	return response.resultTimestamp.Format(time.RFC3339)
+}
+
 // GetValidity gets the validity of the response
 func (response NumberLookupResponse) GetValidity() int {
 	return response.validity
@@ -382,6 +508,9 @@ func (v *jsonNumberLookupResponseEmbedded) Status() error {
 // NumberLookupResponses struct
 type NumberLookupResponses struct {
 	Responses *[]NumberLookupResponse
+
+	// Concurrency is the maximum amount of concurrent calls made by Status, DefaultStatusConcurrency when 0
+	Concurrency int
 }
 
 type jsonNumberLookupResponses struct {
@@ -404,6 +533,15 @@ func (responses *NumberLookupResponses) UnmarshalJSON(j []byte) error {
 	return nil
 }
 
+func (responses *NumberLookupResponses) setClient(c *Client) {
+	if responses.Responses == nil {
+		return
+	}
+	for i := range *responses.Responses {
+		(*responses.Responses)[i].client = c
+	}
+}
+
 // GetItems returns all numberlookup responses
 func (responses NumberLookupResponses) GetItems() []NumberLookupResponse {
 	return *responses.Responses
@@ -411,38 +549,120 @@ func (responses NumberLookupResponses) GetItems() []NumberLookupResponse {
 
 // Status requests the status of all numberlookup responses
 func (responses *NumberLookupResponses) Status() error {
-	newItems := []NumberLookupResponse{}
-	for _, item := range *responses.Responses {
-		err := item.Status()
-		if err != nil {
-			return err
+	return responses.StatusContext(context.Background())
+}
+
+// StatusContext requests the status of all numberlookup responses using ctx for the calls, at most
+// Concurrency at a time. The responses are updated in place, when some of the calls fail a *StatusError
+// is returned containing the failed message ids.
+func (responses *NumberLookupResponses) StatusContext(ctx context.Context) error {
+	if responses.Responses == nil {
+		return nil
+	}
+	items := *responses.Responses
+
+	return statusAll(ctx, len(items), responses.Concurrency, func(ctx context.Context, i int) (string, error) {
+		return items[i].GetMessageID(), items[i].StatusContext(ctx)
+	})
+}
+
+// waitNumberLookups requests the status of the items without a final status every interval until all
+// are final, update is called for every item once and after every status change. With an interval of 0
+// update is called once for every item and no status is requested.
+func waitNumberLookups(
+	ctx context.Context,
+	items []NumberLookupResponse,
+	interval time.Duration,
+	update func(response *NumberLookupResponse) error,
+) error {
+	indexes := make([]int, len(items))
+	for i := range items {
+		indexes[i] = i
+	}
+
+	var statusErr error
+	for {
+		var pending []int
+		for _, i := range indexes {
+			if err := update(&items[i]); err != nil {
+				return err
+			}
+			if !items[i].GetStatusCode().IsFinal() {
+				pending = append(pending, i)
+			}
 		}
-		newItems = append(newItems, item)
+		if statusErr != nil {
+			return statusErr
+		}
+		if len(pending) == 0 || interval <= 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		batch := make([]NumberLookupResponse, len(pending))
+		for j, i := range pending {
+			batch[j] = items[i]
+		}
+		statusErr = (&NumberLookupResponses{Responses: &batch}).StatusContext(ctx)
+		for j, i := range pending {
+			items[i] = batch[j]
+		}
+		indexes = pending
 	}
-	// all went well update Items
-	responses.Responses = &newItems
-	return nil
 }
 
-// NewNumberLookupRequest creates a new verificationParam using a recipient (the only required var)
-func NewNumberLookupRequest(numbers []Recipient) *NumberLookupRequest {
-	params := &NumberLookupRequest{
-		numbers: numbers,
+// NewNumberLookupRequest creates a new numberlookup request for numbers, see SetNumbers
+func NewNumberLookupRequest(numbers []Recipient) (*NumberLookupRequest, error) {
+	return DefaultClient.NewNumberLookupRequest(numbers)
+}
+
+// NewNumberLookupRequest creates a new numberlookup request that will be submitted using the client, the
+// numbers are validated and normalised using ParseRecipient
+func (c *Client) NewNumberLookupRequest(numbers []Recipient) (*NumberLookupRequest, error) {
+	params := &NumberLookupRequest{client: c}
+	if err := params.SetNumbers(numbers); err != nil {
+		return nil, err
 	}
-	return params
+	return params, nil
 }
 
 // NumberLookupSubmit creates a new numberlookup and submits it
 func NumberLookupSubmit(numbers interface{}) (*NumberLookupResponses, error) {
+	return DefaultClient.NumberLookupSubmit(numbers)
+}
+
+// NumberLookupSubmit creates a new numberlookup and submits it using the client
+func (c *Client) NumberLookupSubmit(numbers interface{}) (*NumberLookupResponses, error) {
 	r, err := convertRecipients(numbers)
 	if err != nil {
 		return nil, err
 	}
-	return NewNumberLookupRequest(r).Submit()
+	request, err := c.NewNumberLookupRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	return request.Submit()
 }
 
 // NumberLookupStatus creates a new numberlookup with id and requests the status
 func NumberLookupStatus(messageID string) (*NumberLookupResponse, error) {
+	return DefaultClient.NumberLookupStatus(messageID)
+}
+
+// NumberLookupStatus creates a new numberlookup with id and requests the status using the client
+func (c *Client) NumberLookupStatus(messageID string) (*NumberLookupResponse, error) {
+	return c.NumberLookupStatusContext(context.Background(), messageID)
+}
+
+// NumberLookupStatusContext requests the status of a numberlookup by id using ctx for the call
+func (c *Client) NumberLookupStatusContext(ctx context.Context, messageID string) (*NumberLookupResponse, error) {
 	apiURL, err := GetURLFor(fmt.Sprintf("numberlookup/submit/%s", url.PathEscape(messageID)))
 	if err != nil {
 		return nil, err
@@ -451,14 +671,13 @@ func NumberLookupStatus(messageID string) (*NumberLookupResponse, error) {
 	numberLookupResponse := &NumberLookupResponse{
 		messageID: messageID,
 		links:     createSelfLinks(apiURL),
+		client:    c,
 	}
 
-	err = numberLookupResponse.Status()
-	// were we able to find it ?
-	if apiError, ok := err.(APIError); ok {
-		if apiError.Status() == http.StatusNotFound {
-			return nil, nil
-		}
+	err = numberLookupResponse.StatusContext(ctx)
+	// were we able to find it ? if not the error matches ErrNotFound
+	if errors.Is(err, ErrNotFound) {
+		return nil, err
 	}
 
 	return numberLookupResponse, err
@@ -486,15 +705,36 @@ func (p NumberLookupPollResults) GetItems() []NumberLookupResponse {
 	return *p.Embedded.Messages
 }
 
+func (p *NumberLookupPollResults) setClient(c *Client) {
+	p.client = c
+	if p.Embedded == nil || p.Embedded.Messages == nil {
+		return
+	}
+	for i := range *p.Embedded.Messages {
+		(*p.Embedded.Messages)[i].client = c
+	}
+}
+
 // NewNumberLookupPoll creates a new NumberLookupPoll
 func NewNumberLookupPoll() *NumberLookupPollResults {
+	return DefaultClient.NewNumberLookupPoll()
+}
+
+// NewNumberLookupPoll creates a new NumberLookupPoll using the client
+func (c *Client) NewNumberLookupPoll() *NumberLookupPollResults {
 	params := &NumberLookupPollResults{}
+	params.client = c
 	return params
 }
 
 // NumberLookupPollStatus [todo: refactor]
 func NumberLookupPollStatus() (*NumberLookupPollResults, error) {
-	request := NewNumberLookupPoll()
+	return DefaultClient.NumberLookupPollStatus()
+}
+
+// NumberLookupPollStatus retrieves the numberlookup poll results using the client
+func (c *Client) NumberLookupPollStatus() (*NumberLookupPollResults, error) {
+	request := c.NewNumberLookupPoll()
 	err := request.Status()
 
 	return request, err
@@ -502,13 +742,20 @@ func NumberLookupPollStatus() (*NumberLookupPollResults, error) {
 
 // Status requests the status of a NumberLookupPollResults
 func (p *NumberLookupPollResults) Status() error {
+	return p.StatusContext(context.Background())
+}
+
+// StatusContext requests the status of a NumberLookupPollResults using ctx for the call
+func (p *NumberLookupPollResults) StatusContext(ctx context.Context) error {
 	apiURL, err := p.getURL()
 	if err != nil {
 		return err
 	}
-	resp := NewNumberLookupPoll()
-	err = p.status(apiURL, resp)
+	resp := &NumberLookupPollResults{}
+	err = p.status(ctx, EndpointNumberLookupPoll, apiURL, resp)
 	if err == nil {
+		resp.setClient(p.client)
+		resp.deleteEndpoint = EndpointNumberLookupPollDelete
 		*p = *resp
 	}
 	return err
@@ -7,7 +7,9 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	twizo "github.com/twizoapi/lib-api-go"
 	. "github.com/twizoapi/lib-api-go/testing"
@@ -155,3 +157,37 @@ func TestNumberLookupSubmit(t *testing.T) {
 		t.Fatalf("Invalid application tag expecting [%s] got [%v]", data.ApplicationTag, items[0].GetApplicationTag())
 	}
 }
+
+func TestNumberLookupValidityAndResultTimestamp(t *testing.T) {
+	request, err := twizo.NewNumberLookupRequest([]twizo.Recipient{"31600000000"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := request.SetValidity(time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if err := request.SetValidity(twizo.NumberLookupValidityMax + time.Second); err == nil {
+		t.Fatal("Expecting error for validity above the maximum got [nil]")
+	}
+
+	j, err := json.Marshal(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(j), `"validity":60`) {
+		t.Fatalf("Invalid validity expecting [60] got [%s]", j)
+	}
+
+	response := &twizo.NumberLookupResponse{}
+	if err := json.Unmarshal([]byte(`{"resultTimestamp":"2017-03-16T12:00:00Z"}`), response); err != nil {
+		t.Fatal(err)
+	}
+	expect := time.Date(2017, 3, 16, 12, 0, 0, 0, time.UTC)
+	if response.GetResultTimestamp() == nil || !response.GetResultTimestamp().Equal(expect) {
+		t.Fatalf("Invalid resultTimestamp expecting [%s] got [%v]", expect, response.GetResultTimestamp())
+	}
+
+	if err := json.Unmarshal([]byte(`{"resultTimestamp":"yesterday"}`), response); err == nil {
+		t.Fatal("Expecting error for invalid resultTimestamp got [nil]")
+	}
+}
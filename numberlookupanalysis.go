@@ -0,0 +1,174 @@
+package twizo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// NumberLookupPorted is the typed ported state of a numberlookup
+type NumberLookupPorted int
+
+// All ported states
+const (
+	NumberLookupPortedUnknown NumberLookupPorted = iota
+	NumberLookupPortedYes
+	NumberLookupPortedNo
+)
+
+// String returns the name of the ported state
+func (p NumberLookupPorted) String() string {
+	return numberLookupFlagString(int(p))
+}
+
+// NumberLookupRoaming is the typed roaming state of a numberlookup
+type NumberLookupRoaming int
+
+// All roaming states
+const (
+	NumberLookupRoamingUnknown NumberLookupRoaming = iota
+	NumberLookupRoamingYes
+	NumberLookupRoamingNo
+)
+
+// String returns the name of the roaming state
+func (r NumberLookupRoaming) String() string {
+	return numberLookupFlagString(int(r))
+}
+
+func numberLookupFlagString(flag int) string {
+	switch flag {
+	case 1:
+		return "yes"
+	case 2:
+		return "no"
+	}
+	return "unknown"
+}
+
+// parseNumberLookupFlag returns 1 for yes, 2 for no and 0 for all other values
+func parseNumberLookupFlag(value string) int {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "yes", "true", "1":
+		return 1
+	case "no", "false", "0":
+		return 2
+	}
+	return 0
+}
+
+// NumberLookupReachability is the verdict of a numberlookup on whether the number can be reached
+type NumberLookupReachability int
+
+// All reachability verdicts
+const (
+	// NumberLookupReachabilityUnknown the lookup has no result yet, failed or its result is inconclusive
+	NumberLookupReachabilityUnknown NumberLookupReachability = iota
+
+	// NumberLookupReachabilityValid the number exists and is reachable
+	NumberLookupReachabilityValid
+
+	// NumberLookupReachabilityInvalid the number does not exist or can not receive messages
+	NumberLookupReachabilityInvalid
+
+	// NumberLookupReachabilityAbsent the number exists but the phone is switched off or out of coverage
+	NumberLookupReachabilityAbsent
+)
+
+// String returns the name of the reachability verdict
+func (r NumberLookupReachability) String() string {
+	switch r {
+	case NumberLookupReachabilityValid:
+		return "valid"
+	case NumberLookupReachabilityInvalid:
+		return "invalid"
+	case NumberLookupReachabilityAbsent:
+		return "absent"
+	}
+	return "unknown"
+}
+
+// NumberLookupReasonReachability maps the reason code of a rejected or undelivered numberlookup to a
+// reachability verdict, reason codes that are not in the map are unknown. By default it contains the
+// GSM MAP errors that identify the state of the subscriber, it can be changed before use.
+var NumberLookupReasonReachability = map[int]NumberLookupReachability{
+	1:  NumberLookupReachabilityInvalid, // unknown subscriber
+	5:  NumberLookupReachabilityInvalid, // unidentified subscriber
+	9:  NumberLookupReachabilityInvalid, // illegal subscriber
+	11: NumberLookupReachabilityInvalid, // teleservice not provisioned
+	12: NumberLookupReachabilityInvalid, // illegal equipment
+	13: NumberLookupReachabilityInvalid, // call barred
+	6:  NumberLookupReachabilityAbsent,  // absent subscriber sm
+	27: NumberLookupReachabilityAbsent,  // absent subscriber
+	31: NumberLookupReachabilityAbsent,  // subscriber busy for mt sms
+}
+
+// NetworkCode is the mobile network code of a numberlookup split into the mobile country code (MCC) and
+// the mobile network code (MNC)
+type NetworkCode struct {
+	MCC string
+	MNC string
+}
+
+// String returns the network code as MCC followed by MNC
+func (n NetworkCode) String() string {
+	return n.MCC + n.MNC
+}
+
+// ParseNetworkCode splits a network code of 5 or 6 digits (a 2 or 3 digit MNC) into MCC and MNC
+func ParseNetworkCode(code string) (NetworkCode, error) {
+	if len(code) != 5 && len(code) != 6 {
+		return NetworkCode{}, fmt.Errorf("network code [%s] should have 5 or 6 digits", code)
+	}
+	if _, err := strconv.ParseUint(code, 10, 32); err != nil {
+		return NetworkCode{}, fmt.Errorf("network code [%s] should only contain digits", code)
+	}
+	return NetworkCode{MCC: code[:3], MNC: code[3:]}, nil
+}
+
+// GetPortedStatus returns the typed ported state of the number
+func (response NumberLookupResponse) GetPortedStatus() NumberLookupPorted {
+	return NumberLookupPorted(parseNumberLookupFlag(response.ported))
+}
+
+// GetRoamingStatus returns the typed roaming state of the number
+func (response NumberLookupResponse) GetRoamingStatus() NumberLookupRoaming {
+	return NumberLookupRoaming(parseNumberLookupFlag(response.roaming))
+}
+
+// GetNetwork returns the network code split into MCC and MNC, nil when there is no (valid) network code
+func (response NumberLookupResponse) GetNetwork() *NetworkCode {
+	if response.networkCode == nil {
+		return nil
+	}
+	// the MCC never starts with 0, so no digits are lost by the api returning the code as number
+	network, err := ParseNetworkCode(strconv.Itoa(*response.networkCode))
+	if err != nil {
+		return nil
+	}
+	return &network
+}
+
+// GetCountry returns the ISO 3166-1 alpha-2 country of the number, the country code of the response when
+// set or else the country of the calling code of the number. Numbers with a calling code shared by
+// multiple countries are attributed to the largest of them, example US for 1.
+func (response NumberLookupResponse) GetCountry() string {
+	if response.countryCode != nil && *response.countryCode != "" {
+		return strings.ToUpper(*response.countryCode)
+	}
+	return recipientCountry(Recipient(strings.TrimPrefix(response.number, "+")))
+}
+
+// GetReachability returns the verdict on whether the number can be reached, derived from the status and
+// the reason code using NumberLookupReasonReachability
+func (response NumberLookupResponse) GetReachability() NumberLookupReachability {
+	switch response.statusCode {
+	case NumberLookupStatusCodeDelivered:
+		return NumberLookupReachabilityValid
+	case NumberLookupStatusCodeRejected, NumberLookupStatusCodeUndelivered:
+		if response.reasonCode != nil {
+			return NumberLookupReasonReachability[*response.reasonCode]
+		}
+	}
+	return NumberLookupReachabilityUnknown
+}
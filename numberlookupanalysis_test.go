@@ -0,0 +1,127 @@
+package twizo_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	twizo "github.com/twizoapi/lib-api-go"
+	. "github.com/twizoapi/lib-api-go/testing"
+)
+
+func init() {
+	twizo.APIKey = TestAPIKey
+	twizo.RegionCurrent = TestRegion
+}
+
+func TestNumberLookupResponseAnalysis(t *testing.T) {
+	tests := []struct {
+		json         string
+		ported       twizo.NumberLookupPorted
+		roaming      twizo.NumberLookupRoaming
+		network      string
+		country      string
+		reachability twizo.NumberLookupReachability
+	}{
+		{
+			`{"number":"31600000001","isPorted":"Yes","isRoaming":"No","networkCode":20408,"statusCode":1}`,
+			twizo.NumberLookupPortedYes,
+			twizo.NumberLookupRoamingNo,
+			"204 08",
+			"NL",
+			twizo.NumberLookupReachabilityValid,
+		},
+		{
+			`{"number":"15550000001","isPorted":"no","isRoaming":"yes","networkCode":310260,"statusCode":1}`,
+			twizo.NumberLookupPortedNo,
+			twizo.NumberLookupRoamingYes,
+			"310 260",
+			"US",
+			twizo.NumberLookupReachabilityValid,
+		},
+		{
+			`{"number":"15550000001","countryCode":"ca","isPorted":"Unknown","statusCode":7,"reasonCode":1}`,
+			twizo.NumberLookupPortedUnknown,
+			twizo.NumberLookupRoamingUnknown,
+			"",
+			"CA",
+			twizo.NumberLookupReachabilityInvalid,
+		},
+		{
+			`{"number":"447700900123","statusCode":2,"reasonCode":27}`,
+			twizo.NumberLookupPortedUnknown,
+			twizo.NumberLookupRoamingUnknown,
+			"",
+			"GB",
+			twizo.NumberLookupReachabilityAbsent,
+		},
+		{
+			`{"number":"80012345678","statusCode":7,"reasonCode":34}`,
+			twizo.NumberLookupPortedUnknown,
+			twizo.NumberLookupRoamingUnknown,
+			"",
+			"",
+			twizo.NumberLookupReachabilityUnknown,
+		},
+		{
+			`{"number":"31600000001","networkCode":204,"statusCode":4,"reasonCode":1}`,
+			twizo.NumberLookupPortedUnknown,
+			twizo.NumberLookupRoamingUnknown,
+			"",
+			"NL",
+			twizo.NumberLookupReachabilityUnknown,
+		},
+	}
+
+	for _, test := range tests {
+		response := &twizo.NumberLookupResponse{}
+		if err := json.Unmarshal([]byte(test.json), response); err != nil {
+			t.Fatal(err)
+		}
+
+		if response.GetPortedStatus() != test.ported {
+			t.Errorf("Invalid ported for %s expecting [%s] got [%s]", test.json, test.ported, response.GetPortedStatus())
+		}
+		if response.GetRoamingStatus() != test.roaming {
+			t.Errorf(
+				"Invalid roaming for %s expecting [%s] got [%s]",
+				test.json,
+				test.roaming,
+				response.GetRoamingStatus(),
+			)
+		}
+		network := ""
+		if n := response.GetNetwork(); n != nil {
+			network = n.MCC + " " + n.MNC
+		}
+		if network != test.network {
+			t.Errorf("Invalid network for %s expecting [%s] got [%s]", test.json, test.network, network)
+		}
+		if response.GetCountry() != test.country {
+			t.Errorf("Invalid country for %s expecting [%s] got [%s]", test.json, test.country, response.GetCountry())
+		}
+		if response.GetReachability() != test.reachability {
+			t.Errorf(
+				"Invalid reachability for %s expecting [%s] got [%s]",
+				test.json,
+				test.reachability,
+				response.GetReachability(),
+			)
+		}
+	}
+}
+
+func TestParseNetworkCode(t *testing.T) {
+	network, err := twizo.ParseNetworkCode("20404")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if network.MCC != "204" || network.MNC != "04" || network.String() != "20404" {
+		t.Fatalf("Invalid network expecting [204 04] got [%s %s]", network.MCC, network.MNC)
+	}
+
+	for _, code := range []string{"2040", "2040412", "204a4"} {
+		if _, err := twizo.ParseNetworkCode(code); err == nil {
+			t.Errorf("Expecting error for [%s] got [nil]", code)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package twizo
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultNumberLookupCacheTTL is the default time a numberlookup result is cached
+	DefaultNumberLookupCacheTTL = 24 * time.Hour
+
+	// DefaultNumberLookupCacheSize is the default amount of numbers kept by NewMemoryNumberLookupStore
+	DefaultNumberLookupCacheSize = 10000
+)
+
+// NumberLookupStore stores the numberlookup results of a NumberLookupCache, it should be safe for
+// concurrent use
+type NumberLookupStore interface {
+	// Get returns the result of number, nil when it is not stored or expired
+	Get(ctx context.Context, number Recipient) (*NumberLookupResponse, error)
+
+	// Set stores the result of number until ttl has passed
+	Set(ctx context.Context, number Recipient, response *NumberLookupResponse, ttl time.Duration) error
+}
+
+// MemoryNumberLookupStore is a NumberLookupStore in memory, when it is full the least recently used
+// number is removed
+type MemoryNumberLookupStore struct {
+	size int
+
+	mu      sync.Mutex
+	order   *list.List
+	entries map[Recipient]*list.Element
+}
+
+type memoryNumberLookupEntry struct {
+	number   Recipient
+	response NumberLookupResponse
+	expires  time.Time
+}
+
+// NewMemoryNumberLookupStore creates a new store in memory keeping at most size numbers,
+// DefaultNumberLookupCacheSize when 0
+func NewMemoryNumberLookupStore(size int) *MemoryNumberLookupStore {
+	if size <= 0 {
+		size = DefaultNumberLookupCacheSize
+	}
+	return &MemoryNumberLookupStore{
+		size:    size,
+		order:   list.New(),
+		entries: map[Recipient]*list.Element{},
+	}
+}
+
+// Get returns a copy of the result of number, nil when it is not stored or expired
+func (s *MemoryNumberLookupStore) Get(_ context.Context, number Recipient) (*NumberLookupResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	element, ok := s.entries[number]
+	if !ok {
+		return nil, nil
+	}
+	entry := element.Value.(*memoryNumberLookupEntry)
+	if !time.Now().Before(entry.expires) {
+		s.order.Remove(element)
+		delete(s.entries, number)
+		return nil, nil
+	}
+
+	s.order.MoveToFront(element)
+	response := entry.response
+	return &response, nil
+}
+
+// Set stores a copy of the result of number until ttl has passed
+func (s *MemoryNumberLookupStore) Set(
+	_ context.Context,
+	number Recipient,
+	response *NumberLookupResponse,
+	ttl time.Duration,
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := &memoryNumberLookupEntry{number: number, response: *response, expires: time.Now().Add(ttl)}
+	if element, ok := s.entries[number]; ok {
+		element.Value = entry
+		s.order.MoveToFront(element)
+		return nil
+	}
+
+	s.entries[number] = s.order.PushFront(entry)
+	for s.order.Len() > s.size {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.entries, oldest.Value.(*memoryNumberLookupEntry).number)
+	}
+	return nil
+}
+
+// Len returns the amount of numbers stored, including expired ones that were not removed yet
+func (s *MemoryNumberLookupStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.order.Len()
+}
+
+// NumberLookupCache looks up numbers, results are cached so a number is only submitted again after TTL.
+//
+// Only final results are cached, expired numberlookups are not. Lookup waits for the results of the
+// submitted numbers by requesting their status every Interval. When the numberlookups are made with a
+// callback or polling result type, pass Update as handler to a CallbackHandler or NumberLookupPoller and
+// set Interval to 0.
+type NumberLookupCache struct {
+	// TTL is the time a result is cached, DefaultNumberLookupCacheTTL when 0
+	TTL time.Duration
+
+	// Interval between the status lookups of submitted numbers, 0 does not wait for the results
+	Interval time.Duration
+
+	// Store keeps the results, a MemoryNumberLookupStore of DefaultNumberLookupCacheSize when nil
+	Store NumberLookupStore
+
+	client *Client
+	once   sync.Once
+}
+
+// NewNumberLookupCache creates a new numberlookup cache with the default ttl, interval and store
+func NewNumberLookupCache() *NumberLookupCache {
+	return DefaultClient.NewNumberLookupCache()
+}
+
+// NewNumberLookupCache creates a new numberlookup cache that will submit using the client
+func (c *Client) NewNumberLookupCache() *NumberLookupCache {
+	return &NumberLookupCache{
+		TTL:      DefaultNumberLookupCacheTTL,
+		Interval: DefaultNumberLookupInterval,
+		Store:    NewMemoryNumberLookupStore(DefaultNumberLookupCacheSize),
+		client:   c,
+	}
+}
+
+func (cache *NumberLookupCache) store() NumberLookupStore {
+	cache.once.Do(func() {
+		if cache.Store == nil {
+			cache.Store = NewMemoryNumberLookupStore(DefaultNumberLookupCacheSize)
+		}
+	})
+	return cache.Store
+}
+
+func (cache *NumberLookupCache) ttl() time.Duration {
+	if cache.TTL <= 0 {
+		return DefaultNumberLookupCacheTTL
+	}
+	return cache.TTL
+}
+
+// Lookup returns the result of every number, see LookupContext
+func (cache *NumberLookupCache) Lookup(numbers []Recipient) (*NumberLookupResponses, error) {
+	return cache.LookupContext(context.Background(), numbers)
+}
+
+// LookupContext returns the result of every number, in the order of numbers. The numbers are normalised
+// using ParseRecipient, only the numbers that are not cached are submitted (once). When waiting for the
+// results fails or ctx is done, the error is returned together with the results known at that time.
+func (cache *NumberLookupCache) LookupContext(
+	ctx context.Context,
+	numbers []Recipient,
+) (*NumberLookupResponses, error) {
+	client := clientOrDefault(cache.client)
+	numbers, err := ParseRecipients(numbers, client.getDefaultCountry())
+	if err != nil {
+		return nil, err
+	}
+
+	results := map[Recipient]*NumberLookupResponse{}
+	var missing []Recipient
+	for _, number := range numbers {
+		if _, ok := results[number]; ok {
+			continue
+		}
+		response, err := cache.store().Get(ctx, number)
+		if err != nil {
+			return nil, err
+		}
+		if response == nil {
+			missing = append(missing, number)
+		} else {
+			response.client = client
+		}
+		// a missing number is set to nil so it is only submitted once
+		results[number] = response
+	}
+
+	if len(missing) > 0 {
+		submitted, err := cache.submit(ctx, client, missing)
+		for i := range submitted {
+			results[normaliseRecipient(Recipient(submitted[i].GetNumber()), client.getDefaultCountry())] = &submitted[i]
+		}
+		if err != nil {
+			return mergeNumberLookupResults(numbers, results), err
+		}
+	}
+
+	return mergeNumberLookupResults(numbers, results), nil
+}
+
+// submit submits numbers and waits for the results when Interval is set, the results are cached
+func (cache *NumberLookupCache) submit(
+	ctx context.Context,
+	client *Client,
+	numbers []Recipient,
+) ([]NumberLookupResponse, error) {
+	request, err := client.NewNumberLookupRequest(numbers)
+	if err != nil {
+		return nil, err
+	}
+	responses, err := request.SubmitContext(ctx)
+	if err != nil || responses.Responses == nil {
+		return nil, err
+	}
+	items := *responses.Responses
+
+	return items, waitNumberLookups(ctx, items, cache.Interval, func(response *NumberLookupResponse) error {
+		return cache.Update(ctx, response)
+	})
+}
+
+// Update caches the result by its normalised number when it is final, it can be used as handler of a
+// CallbackHandler or NumberLookupPoller
+func (cache *NumberLookupCache) Update(ctx context.Context, response *NumberLookupResponse) error {
+	code := response.GetStatusCode()
+	if !code.IsFinal() || code == NumberLookupStatusCodeExpired {
+		return nil
+	}
+	number := normaliseRecipient(Recipient(response.GetNumber()), clientOrDefault(cache.client).getDefaultCountry())
+	return cache.store().Set(ctx, number, response, cache.ttl())
+}
+
+// mergeNumberLookupResults returns the result of every number in order, numbers without a result are left out
+func mergeNumberLookupResults(
+	numbers []Recipient,
+	results map[Recipient]*NumberLookupResponse,
+) *NumberLookupResponses {
+	items := make([]NumberLookupResponse, 0, len(numbers))
+	for _, number := range numbers {
+		if response := results[number]; response != nil {
+			items = append(items, *response)
+		}
+	}
+	return &NumberLookupResponses{Responses: &items}
+}
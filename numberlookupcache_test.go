@@ -0,0 +1,287 @@
+package twizo_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	twizo "github.com/twizoapi/lib-api-go"
+	. "github.com/twizoapi/lib-api-go/testing"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+func init() {
+	twizo.APIKey = TestAPIKey
+	twizo.RegionCurrent = TestRegion
+}
+
+func numberLookupStatusURL(messageID string) string {
+	return fmt.Sprintf(
+		"https://%s/%s/numberlookup/submit/%s",
+		twizo.GetHostForRegion(TestRegion),
+		twizo.ClientAPIVersion,
+		messageID,
+	)
+}
+
+func numberLookupStatusJSON(number string, statusCode twizo.NumberLookupStatusCode, operator string) string {
+	return fmt.Sprintf(
+		`{"messageId":"id-%s","number":"%s","statusCode":%d,"operator":"%s","_links":{"self":{"href":"%s"}}}`,
+		number,
+		number,
+		statusCode,
+		operator,
+		numberLookupStatusURL("id-"+number),
+	)
+}
+
+// registerNumberLookups mocks the numberlookup submit, the submitted numbers are pending until their
+// status is requested, numbers are returned by the function
+func registerNumberLookups(results map[string]string) func() []string {
+	var mu sync.Mutex
+	var submitted []string
+
+	httpmock.RegisterResponder(
+		http.MethodPost,
+		fmt.Sprintf("https://%s/%s/numberlookup/submit", twizo.GetHostForRegion(TestRegion), twizo.ClientAPIVersion),
+		func(req *http.Request) (*http.Response, error) {
+			body, _ := ioutil.ReadAll(req.Body)
+			var data struct {
+				Numbers []string `json:"numbers"`
+			}
+			if err := json.Unmarshal(body, &data); err != nil {
+				return httpmock.NewStringResponse(http.StatusBadRequest, ""), nil
+			}
+
+			mu.Lock()
+			submitted = append(submitted, data.Numbers...)
+			mu.Unlock()
+
+			var items []string
+			for _, number := range data.Numbers {
+				items = append(items, numberLookupStatusJSON(number, twizo.NumberLookupStatusCodeNoStatus, ""))
+			}
+			return httpmock.NewStringResponse(
+				http.StatusCreated,
+				fmt.Sprintf(`{"_embedded":{"items":[%s]}}`, strings.Join(items, ",")),
+			), nil
+		},
+	)
+
+	for number, operator := range results {
+		number, operator := number, operator
+		httpmock.RegisterResponder(
+			http.MethodGet,
+			numberLookupStatusURL("id-"+number),
+			func(req *http.Request) (*http.Response, error) {
+				return httpmock.NewStringResponse(
+					http.StatusOK,
+					numberLookupStatusJSON(number, twizo.NumberLookupStatusCodeDelivered, operator),
+				), nil
+			},
+		)
+	}
+
+	return func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string{}, submitted...)
+	}
+}
+
+func TestNumberLookupCache(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	submitted := registerNumberLookups(map[string]string{
+		"31600000001": "KPN",
+		"31600000002": "Vodafone",
+		"31600000003": "T-Mobile",
+	})
+
+	cache := twizo.NewNumberLookupCache()
+	cache.Interval = time.Millisecond
+
+	responses, err := cache.Lookup([]twizo.Recipient{"31600000001", "+31 6 00000002", "31600000001"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(submitted()) != "[31600000001 31600000002]" {
+		t.Fatalf("Invalid submitted numbers expecting [[31600000001 31600000002]] got [%v]", submitted())
+	}
+
+	responses, err = cache.Lookup([]twizo.Recipient{"31600000003", "31600000002", "31600000001"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(submitted()) != "[31600000001 31600000002 31600000003]" {
+		t.Fatalf("Invalid submitted numbers expecting only [31600000003] to be submitted again got [%v]", submitted())
+	}
+
+	var operators []string
+	for _, response := range responses.GetItems() {
+		if response.GetStatusCode() != twizo.NumberLookupStatusCodeDelivered {
+			t.Fatalf("Invalid status of [%s] got [%d]", response.GetNumber(), response.GetStatusCode())
+		}
+		operators = append(operators, *response.GetOperator())
+	}
+	if fmt.Sprint(operators) != "[T-Mobile Vodafone KPN]" {
+		t.Fatalf("Invalid operators expecting [[T-Mobile Vodafone KPN]] got [%v]", operators)
+	}
+}
+
+func TestNumberLookupCacheFormattedNumber(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	// the api echoes the number with a +
+	submits := 0
+	httpmock.RegisterResponder(
+		http.MethodPost,
+		fmt.Sprintf("https://%s/%s/numberlookup/submit", twizo.GetHostForRegion(TestRegion), twizo.ClientAPIVersion),
+		func(req *http.Request) (*http.Response, error) {
+			submits++
+			return httpmock.NewStringResponse(
+				http.StatusCreated,
+				fmt.Sprintf(
+					`{"_embedded":{"items":[{"messageId":"id-1","number":"+31600000001","statusCode":0,`+
+						`"_links":{"self":{"href":"%s"}}}]}}`,
+					numberLookupStatusURL("id-1"),
+				),
+			), nil
+		},
+	)
+	httpmock.RegisterResponder(
+		http.MethodGet,
+		numberLookupStatusURL("id-1"),
+		func(req *http.Request) (*http.Response, error) {
+			return httpmock.NewStringResponse(
+				http.StatusOK,
+				fmt.Sprintf(
+					`{"messageId":"id-1","number":"+31600000001","statusCode":1,"operator":"KPN",`+
+						`"_links":{"self":{"href":"%s"}}}`,
+					numberLookupStatusURL("id-1"),
+				),
+			), nil
+		},
+	)
+
+	cache := twizo.NewNumberLookupCache()
+	cache.Interval = time.Millisecond
+
+	for i := 0; i < 2; i++ {
+		responses, err := cache.Lookup([]twizo.Recipient{"31600000001"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if items := responses.GetItems(); len(items) != 1 || *items[0].GetOperator() != "KPN" {
+			t.Fatalf("Invalid results of lookup [%d] expecting [KPN] got [%v]", i+1, items)
+		}
+	}
+	if submits != 1 {
+		t.Fatalf("Invalid amount of submits expecting the cached result to be used got [%d]", submits)
+	}
+}
+
+func TestNumberLookupCacheWithoutWaiting(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	submitted := registerNumberLookups(nil)
+
+	cache := twizo.NewNumberLookupCache()
+	cache.Interval = 0
+
+	// pending results are not cached until they are passed to Update
+	for i := 0; i < 2; i++ {
+		responses, err := cache.Lookup([]twizo.Recipient{"31600000001"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(responses.GetItems()) != 1 {
+			t.Fatalf("Invalid amount of results expecting [1] got [%d]", len(responses.GetItems()))
+		}
+	}
+	if len(submitted()) != 2 {
+		t.Fatalf("Invalid amount of submits expecting [2] got [%d]", len(submitted()))
+	}
+
+	response := &twizo.NumberLookupResponse{}
+	err := json.Unmarshal(
+		[]byte(numberLookupStatusJSON("31600000001", twizo.NumberLookupStatusCodeDelivered, "KPN")),
+		response,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.Update(context.Background(), response); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.Lookup([]twizo.Recipient{"31600000001"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(submitted()) != 2 {
+		t.Fatalf("Invalid amount of submits expecting [2] got [%d]", len(submitted()))
+	}
+}
+
+func TestMemoryNumberLookupStore(t *testing.T) {
+	ctx := context.Background()
+	store := twizo.NewMemoryNumberLookupStore(2)
+	response := &twizo.NumberLookupResponse{}
+
+	for _, number := range []twizo.Recipient{"31600000001", "31600000002"} {
+		if err := store.Set(ctx, number, response, time.Hour); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 31600000001 is used most recently, so 31600000002 is removed
+	if cached, _ := store.Get(ctx, "31600000001"); cached == nil {
+		t.Fatal("Expecting [31600000001] to be stored")
+	}
+	if err := store.Set(ctx, "31600000003", response, time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if cached, _ := store.Get(ctx, "31600000002"); cached != nil {
+		t.Fatal("Expecting [31600000002] to be removed")
+	}
+	if store.Len() != 2 {
+		t.Fatalf("Invalid length expecting [2] got [%d]", store.Len())
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	if cached, _ := store.Get(ctx, "31600000003"); cached != nil {
+		t.Fatal("Expecting [31600000003] to be expired")
+	}
+}
+
+func TestNumberLookupResponseJSON(t *testing.T) {
+	response := &twizo.NumberLookupResponse{}
+	err := json.Unmarshal(
+		[]byte(numberLookupStatusJSON("31600000001", twizo.NumberLookupStatusCodeDelivered, "KPN")),
+		response,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	j, err := json.Marshal(response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := &twizo.NumberLookupResponse{}
+	if err := json.Unmarshal(j, decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.GetNumber() != "31600000001" || *decoded.GetOperator() != "KPN" ||
+		decoded.GetStatusCode() != twizo.NumberLookupStatusCodeDelivered {
+		t.Fatalf("Invalid decoded response got [%s]", j)
+	}
+}
@@ -0,0 +1,198 @@
+package twizo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrRecipientUnreachable is matched by the skip reason of FilterReachable
+var ErrRecipientUnreachable = errors.New("twizo: recipient not reachable")
+
+// NumberLookupFilter decides whether the recipient of a numberlookup result is sent the message, the
+// returned error is the reason the recipient is skipped
+type NumberLookupFilter func(response *NumberLookupResponse) error
+
+// FilterReachable only passes numbers with a valid reachability verdict, see GetReachability
+func FilterReachable(response *NumberLookupResponse) error {
+	if reachability := response.GetReachability(); reachability != NumberLookupReachabilityValid {
+		return fmt.Errorf("%w: number is %s", ErrRecipientUnreachable, reachability)
+	}
+	return nil
+}
+
+// SkippedRecipient is a recipient that was not sent the message
+type SkippedRecipient struct {
+	Recipient Recipient
+
+	// Lookup is the numberlookup result of the recipient, nil when there is none
+	Lookup *NumberLookupResponse
+
+	// Reason is the error returned by the filter, or the reason there is no result
+	Reason error
+}
+
+// NumberLookupSmsReport is the result of NumberLookupSmsSender.Send
+type NumberLookupSmsReport struct {
+	// Sent are the recipients the message was submitted to, in the order of the request
+	Sent []Recipient
+
+	// Skipped are the recipients that were not sent the message, in the order of the request
+	Skipped []SkippedRecipient
+
+	// Responses are the responses of the submit, nil when all recipients were skipped
+	Responses *SmsResponses
+}
+
+// NumberLookupSmsSender looks up the recipients of a message first and only sends the message to the
+// recipients passing Filter
+type NumberLookupSmsSender struct {
+	// Filter decides which recipients are sent the message, FilterReachable when nil
+	Filter NumberLookupFilter
+
+	// Interval between the status lookups while waiting for the results, DefaultNumberLookupInterval when 0
+	Interval time.Duration
+
+	// MaxWait is the maximum time to wait for the results, recipients without a result by then are
+	// passed to Filter as is. There is no maximum when 0, the wait is only limited by the context.
+	MaxWait time.Duration
+
+	// Cache is used to look up the numbers when set, its Interval is used instead
+	Cache *NumberLookupCache
+
+	client *Client
+}
+
+// NewNumberLookupSmsSender creates a new sender only sending to reachable numbers
+func NewNumberLookupSmsSender() *NumberLookupSmsSender {
+	return DefaultClient.NewNumberLookupSmsSender()
+}
+
+// NewNumberLookupSmsSender creates a new sender only sending to reachable numbers using the client
+func (c *Client) NewNumberLookupSmsSender() *NumberLookupSmsSender {
+	return &NumberLookupSmsSender{
+		Filter:   FilterReachable,
+		Interval: DefaultNumberLookupInterval,
+		client:   c,
+	}
+}
+
+// Send looks up the recipients of request and submits it to the recipients passing the filter
+func (s *NumberLookupSmsSender) Send(request *SmsRequest) (*NumberLookupSmsReport, error) {
+	return s.SendContext(context.Background(), request)
+}
+
+// SendContext looks up the recipients of request and submits it to the recipients passing the filter,
+// request itself is not changed. When the lookups or the submit fail the error is returned, together with
+// the report when the lookups succeeded.
+func (s *NumberLookupSmsSender) SendContext(
+	ctx context.Context,
+	request *SmsRequest,
+) (*NumberLookupSmsReport, error) {
+	results, err := s.lookup(ctx, request.recipients)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := s.Filter
+	if filter == nil {
+		filter = FilterReachable
+	}
+
+	report := &NumberLookupSmsReport{}
+	for _, recipient := range request.recipients {
+		lookup, ok := results[s.normalise(recipient)]
+		if !ok {
+			report.Skipped = append(report.Skipped, SkippedRecipient{
+				Recipient: recipient,
+				Reason:    errors.New("no numberlookup result"),
+			})
+			continue
+		}
+		if err := filter(lookup); err != nil {
+			report.Skipped = append(report.Skipped, SkippedRecipient{
+				Recipient: recipient,
+				Lookup:    lookup,
+				Reason:    err,
+			})
+			continue
+		}
+		report.Sent = append(report.Sent, recipient)
+	}
+
+	if len(report.Sent) == 0 {
+		return report, nil
+	}
+
+	passing := *request
+	passing.recipients = report.Sent
+	report.Responses, err = passing.SubmitContext(ctx)
+	if err != nil {
+		report.Sent = nil
+		return report, err
+	}
+	return report, nil
+}
+
+// lookup returns the numberlookup result per normalised number
+func (s *NumberLookupSmsSender) lookup(
+	ctx context.Context,
+	numbers []Recipient,
+) (map[Recipient]*NumberLookupResponse, error) {
+	waitCtx := ctx
+	if s.MaxWait > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, s.MaxWait)
+		defer cancel()
+	}
+
+	var responses []NumberLookupResponse
+	var err error
+	if s.Cache != nil {
+		var lookups *NumberLookupResponses
+		lookups, err = s.Cache.LookupContext(waitCtx, numbers)
+		if lookups != nil {
+			responses = *lookups.Responses
+		}
+	} else {
+		responses, err = s.submit(waitCtx, numbers)
+	}
+	// the results known when MaxWait is over are used
+	if err != nil && (ctx.Err() != nil || waitCtx.Err() == nil || responses == nil) {
+		return nil, err
+	}
+
+	results := map[Recipient]*NumberLookupResponse{}
+	for i := range responses {
+		results[s.normalise(Recipient(responses[i].GetNumber()))] = &responses[i]
+	}
+	return results, nil
+}
+
+// normalise returns number normalised using the default country of the client
+func (s *NumberLookupSmsSender) normalise(number Recipient) Recipient {
+	return normaliseRecipient(number, clientOrDefault(s.client).getDefaultCountry())
+}
+
+// submit submits the numberlookup of numbers and waits for the results
+func (s *NumberLookupSmsSender) submit(
+	ctx context.Context,
+	numbers []Recipient,
+) ([]NumberLookupResponse, error) {
+	request, err := clientOrDefault(s.client).NewNumberLookupRequest(numbers)
+	if err != nil {
+		return nil, err
+	}
+	lookups, err := request.SubmitContext(ctx)
+	if err != nil || lookups.Responses == nil {
+		return nil, err
+	}
+	items := *lookups.Responses
+
+	interval := s.Interval
+	if interval <= 0 {
+		interval = DefaultNumberLookupInterval
+	}
+	return items, waitNumberLookups(ctx, items, interval, func(*NumberLookupResponse) error { return nil })
+}
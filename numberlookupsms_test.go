@@ -0,0 +1,204 @@
+package twizo_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	twizo "github.com/twizoapi/lib-api-go"
+	. "github.com/twizoapi/lib-api-go/testing"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+func init() {
+	twizo.APIKey = TestAPIKey
+	twizo.RegionCurrent = TestRegion
+}
+
+func TestNumberLookupSmsSender(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	registerNumberLookups(map[string]string{"31600000001": "KPN"})
+	// 31600000002 does not exist, the lookup of 31600000003 never completes
+	httpmock.RegisterResponder(
+		http.MethodGet,
+		numberLookupStatusURL("id-31600000002"),
+		func(req *http.Request) (*http.Response, error) {
+			return httpmock.NewStringResponse(
+				http.StatusOK,
+				fmt.Sprintf(
+					`{"messageId":"id-31600000002","number":"31600000002","statusCode":7,"reasonCode":1,`+
+						`"_links":{"self":{"href":"%s"}}}`,
+					numberLookupStatusURL("id-31600000002"),
+				),
+			), nil
+		},
+	)
+	httpmock.RegisterResponder(
+		http.MethodGet,
+		numberLookupStatusURL("id-31600000003"),
+		func(req *http.Request) (*http.Response, error) {
+			return httpmock.NewStringResponse(
+				http.StatusOK,
+				numberLookupStatusJSON("31600000003", twizo.NumberLookupStatusCodeEnroute, ""),
+			), nil
+		},
+	)
+	submits := 0
+	registerBulkSubmit(&submits)
+
+	request, err := twizo.NewSmsRequest(
+		[]twizo.Recipient{"31600000001", "31600000002", "31600000003"},
+		"Message",
+		"Sender",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sender := twizo.NewNumberLookupSmsSender()
+	sender.Interval = time.Millisecond
+	sender.MaxWait = 50 * time.Millisecond
+
+	report, err := sender.Send(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if submits != 1 || fmt.Sprint(report.Sent) != "[31600000001]" {
+		t.Fatalf("Invalid sent expecting [[31600000001]] in [1] submit got %v in [%d]", report.Sent, submits)
+	}
+	if items := report.Responses.GetItems(); len(items) != 1 || items[0].GetMessageID() != "31600000001" {
+		t.Fatalf("Invalid responses expecting [31600000001] got [%v]", items)
+	}
+
+	if len(report.Skipped) != 2 {
+		t.Fatalf("Invalid amount of skipped recipients expecting [2] got [%d]", len(report.Skipped))
+	}
+	expect := []twizo.NumberLookupReachability{
+		twizo.NumberLookupReachabilityInvalid,
+		twizo.NumberLookupReachabilityUnknown,
+	}
+	for i, skipped := range report.Skipped {
+		if !errors.Is(skipped.Reason, twizo.ErrRecipientUnreachable) {
+			t.Fatalf("Invalid reason for [%s] got [%v]", skipped.Recipient, skipped.Reason)
+		}
+		if skipped.Lookup.GetReachability() != expect[i] {
+			t.Fatalf(
+				"Invalid reachability for [%s] expecting [%s] got [%s]",
+				skipped.Recipient,
+				expect[i],
+				skipped.Lookup.GetReachability(),
+			)
+		}
+	}
+
+	// the request itself is not changed
+	if len(request.GetRecipients()) != 3 {
+		t.Fatalf("Invalid amount of recipients of the request expecting [3] got [%d]", len(request.GetRecipients()))
+	}
+}
+
+func TestNumberLookupSmsSenderFilter(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	registerNumberLookups(map[string]string{"31600000001": "KPN", "31600000002": "Vodafone"})
+	submits := 0
+	registerBulkSubmit(&submits)
+
+	request, err := twizo.NewSmsRequest([]twizo.Recipient{"31600000001", "31600000002"}, "Message", "Sender")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errOperator := errors.New("operator not allowed")
+	sender := twizo.NewNumberLookupSmsSender()
+	sender.Interval = time.Millisecond
+	sender.Filter = func(response *twizo.NumberLookupResponse) error {
+		if *response.GetOperator() != "KPN" {
+			return errOperator
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	report, err := sender.SendContext(ctx, request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(report.Sent) != "[31600000001]" {
+		t.Fatalf("Invalid sent expecting [[31600000001]] got %v", report.Sent)
+	}
+	if len(report.Skipped) != 1 || report.Skipped[0].Reason != errOperator {
+		t.Fatalf("Invalid skipped expecting [31600000002] with [%v] got %v", errOperator, report.Skipped)
+	}
+
+	// nothing is submitted when all recipients are skipped
+	sender.Filter = func(*twizo.NumberLookupResponse) error { return errOperator }
+	report, err = sender.SendContext(ctx, request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if submits != 1 || report.Responses != nil || len(report.Skipped) != 2 {
+		t.Fatalf("Expecting no submit got [%d] submits and report %+v", submits-1, report)
+	}
+}
+
+func TestNumberLookupSmsSenderFormattedNumber(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	// the api echoes the number in another format than it was submitted in
+	httpmock.RegisterResponder(
+		http.MethodPost,
+		fmt.Sprintf("https://%s/%s/numberlookup/submit", twizo.GetHostForRegion(TestRegion), twizo.ClientAPIVersion),
+		func(req *http.Request) (*http.Response, error) {
+			return httpmock.NewStringResponse(
+				http.StatusCreated,
+				fmt.Sprintf(
+					`{"_embedded":{"items":[{"messageId":"id-1","number":"+31600000001","statusCode":0,`+
+						`"_links":{"self":{"href":"%s"}}}]}}`,
+					numberLookupStatusURL("id-1"),
+				),
+			), nil
+		},
+	)
+	httpmock.RegisterResponder(
+		http.MethodGet,
+		numberLookupStatusURL("id-1"),
+		func(req *http.Request) (*http.Response, error) {
+			return httpmock.NewStringResponse(
+				http.StatusOK,
+				fmt.Sprintf(
+					`{"messageId":"id-1","number":"+31 6 00000001","statusCode":1,"operator":"KPN",`+
+						`"_links":{"self":{"href":"%s"}}}`,
+					numberLookupStatusURL("id-1"),
+				),
+			), nil
+		},
+	)
+	submits := 0
+	registerBulkSubmit(&submits)
+
+	request, err := twizo.NewSmsRequest([]twizo.Recipient{"31600000001"}, "Message", "Sender")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender := twizo.NewNumberLookupSmsSender()
+	sender.Interval = time.Millisecond
+
+	report, err := sender.Send(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if submits != 1 || fmt.Sprint(report.Sent) != "[31600000001]" || len(report.Skipped) != 0 {
+		t.Fatalf("Invalid sent expecting [[31600000001]] got %v skipped %v", report.Sent, report.Skipped)
+	}
+}
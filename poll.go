@@ -1,6 +1,7 @@
 package twizo
 
 import (
+	"context"
 	"net/http"
 	"net/url"
 )
@@ -14,10 +15,15 @@ type pollResults struct {
 	BatchID string        `json:"batchId"`
 	Count   int           `json:"count"` // strange was called total_items in smsResponse
 	Links   *HATEOASLinks `json:"_links"`
+	client  *Client
+	// deleteEndpoint the endpoint used to delete the batch
+	deleteEndpoint Endpoint
 }
 
-func (p *pollResults) status(url *url.URL, resp interface{}) error {
-	err := GetClient(RegionCurrent, APIKey).Call(
+func (p *pollResults) status(ctx context.Context, endpoint Endpoint, url *url.URL, resp interface{}) error {
+	err := clientOrDefault(p.client).call(
+		ctx,
+		endpoint,
 		http.MethodGet,
 		url,
 		nil,
@@ -32,6 +38,11 @@ func (p *pollResults) status(url *url.URL, resp interface{}) error {
 }
 
 func (p *pollResults) Delete() error {
+	return p.DeleteContext(context.Background())
+}
+
+// DeleteContext deletes the poll batch using ctx for the call
+func (p *pollResults) DeleteContext(ctx context.Context) error {
 	if p.BatchID == "" {
 		// we can not delete without a batchId,
 		return nil
@@ -39,7 +50,9 @@ func (p *pollResults) Delete() error {
 
 	// we can delete the same thing over and over and still get the
 	// statusNoContent response, so this is safe to do.
-	err := GetClient(RegionCurrent, APIKey).Call(
+	err := clientOrDefault(p.client).call(
+		ctx,
+		p.deleteEndpoint,
 		http.MethodDelete,
 		&p.Links.Self.Href,
 		nil,
@@ -0,0 +1,290 @@
+package twizo
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// DefaultPollMinInterval is the wait after the first empty poll batch
+const DefaultPollMinInterval = 1 * time.Second
+
+// DefaultPollMaxInterval is the maximum wait between empty poll batches
+const DefaultPollMaxInterval = 30 * time.Second
+
+// errPollerNoHandler is returned by Run when the poller has neither a Handler nor a Channel
+var errPollerNoHandler = errors.New("twizo: poller has no Handler or Channel")
+
+// poller contains the loop shared by SmsPoller and NumberLookupPoller, see their fields
+type poller struct {
+	minInterval  time.Duration
+	maxInterval  time.Duration
+	errorHandler func(ctx context.Context, err error)
+}
+
+// run fetches batches until ctx is done, every item of a batch is delivered before the
+// batch is deleted. fetch returns the batch and the amount of items in it.
+func (p *poller) run(
+	ctx context.Context,
+	fetch func(ctx context.Context) (*pollResults, int, error),
+	deliver func(ctx context.Context, i int) error,
+) error {
+	minInterval := p.minInterval
+	if minInterval <= 0 {
+		minInterval = DefaultPollMinInterval
+	}
+	maxInterval := p.maxInterval
+	if maxInterval <= 0 {
+		maxInterval = DefaultPollMaxInterval
+	}
+	if maxInterval < minInterval {
+		maxInterval = minInterval
+	}
+
+	interval := minInterval
+	// backoff waits interval and doubles it, false is returned when ctx is done
+	backoff := func() bool {
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return false
+		case <-timer.C:
+		}
+
+		interval *= 2
+		if interval > maxInterval {
+			interval = maxInterval
+		}
+		return true
+	}
+
+	for {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		batch, count, err := fetch(ctx)
+		if err != nil {
+			if !p.transient(ctx, err) || !backoff() {
+				return p.stop(ctx, err)
+			}
+			continue
+		}
+
+		if count == 0 {
+			// the queue is empty, wait a bit longer every time
+			if !backoff() {
+				return nil
+			}
+			continue
+		}
+		interval = minInterval
+
+		for i := 0; i < count; i++ {
+			if err := deliver(ctx, i); err != nil {
+				// the batch is not deleted, so it will be fetched again
+				return p.stop(ctx, err)
+			}
+		}
+
+		if err := batch.DeleteContext(ctx); err != nil {
+			// the batch will be fetched and delivered again
+			if !p.transient(ctx, err) || !backoff() {
+				return p.stop(ctx, err)
+			}
+		}
+	}
+}
+
+// transient returns true when polling can continue after err, the error is passed to ErrorHandler
+func (p *poller) transient(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if _, ok := isRetryableError(err); !ok {
+		return false
+	}
+	if p.errorHandler != nil {
+		p.errorHandler(ctx, err)
+	}
+	return true
+}
+
+// stop returns err, unless it was caused by ctx being done
+func (p *poller) stop(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+// SmsPoller continuously polls the sms poll queue and passes every sms to Handler,
+// or sends it on Channel when there is no Handler.
+//
+// A batch is only deleted after all its messages were handled. When Handler returns an
+// error Run stops with that error, the batch is not deleted so the messages will be
+// delivered again on the next Run.
+type SmsPoller struct {
+	// Handler is called for every sms in the queue
+	Handler func(ctx context.Context, response *SmsResponse) error
+
+	// Channel receives every sms in the queue when there is no Handler
+	Channel chan<- *SmsResponse
+
+	// MinInterval is the wait after an empty batch, doubled for every next empty batch.
+	// DefaultPollMinInterval when 0 or less.
+	MinInterval time.Duration
+
+	// MaxInterval is the maximum wait between empty batches, at least MinInterval.
+	// DefaultPollMaxInterval when 0 or less.
+	MaxInterval time.Duration
+
+	// ErrorHandler is called with the transient errors of polling (example a timeout or a 503), after
+	// which polling continues with the same backoff as for empty batches. Other errors stop Run.
+	ErrorHandler func(ctx context.Context, err error)
+
+	client *Client
+}
+
+// NewSmsPoller creates a new sms poller calling handler for every sms
+func NewSmsPoller(handler func(ctx context.Context, response *SmsResponse) error) *SmsPoller {
+	return DefaultClient.NewSmsPoller(handler)
+}
+
+// NewSmsPoller creates a new sms poller calling handler for every sms using the client
+func (c *Client) NewSmsPoller(handler func(ctx context.Context, response *SmsResponse) error) *SmsPoller {
+	return &SmsPoller{
+		Handler:     handler,
+		MinInterval: DefaultPollMinInterval,
+		MaxInterval: DefaultPollMaxInterval,
+		client:      c,
+	}
+}
+
+// Run polls until ctx is done, in which case nil is returned, or until polling fails with an error that
+// is not transient or handling fails. An error is returned right away when there is no Handler or Channel.
+func (p *SmsPoller) Run(ctx context.Context) error {
+	if p.Handler == nil && p.Channel == nil {
+		return errPollerNoHandler
+	}
+
+	var results *SmsPollResults
+	return p.loop().run(
+		ctx,
+		func(ctx context.Context) (*pollResults, int, error) {
+			results = clientOrDefault(p.client).NewSmsPoll()
+			if err := results.StatusContext(ctx); err != nil {
+				return nil, 0, err
+			}
+			if results.Embedded == nil || results.Embedded.Messages == nil {
+				return &results.pollResults, 0, nil
+			}
+			return &results.pollResults, len(*results.Embedded.Messages), nil
+		},
+		func(ctx context.Context, i int) error {
+			response := &(*results.Embedded.Messages)[i]
+			if p.Handler != nil {
+				return p.Handler(ctx, response)
+			}
+			select {
+			case p.Channel <- response:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	)
+}
+
+// loop returns the poll loop using the intervals and ErrorHandler of the poller
+func (p *SmsPoller) loop() *poller {
+	return &poller{minInterval: p.MinInterval, maxInterval: p.MaxInterval, errorHandler: p.ErrorHandler}
+}
+
+// NumberLookupPoller continuously polls the numberlookup poll queue and passes every result
+// to Handler, or sends it on Channel when there is no Handler.
+//
+// A batch is only deleted after all its results were handled. When Handler returns an
+// error Run stops with that error, the batch is not deleted so the results will be
+// delivered again on the next Run.
+type NumberLookupPoller struct {
+	// Handler is called for every numberlookup result in the queue
+	Handler func(ctx context.Context, response *NumberLookupResponse) error
+
+	// Channel receives every numberlookup result in the queue when there is no Handler
+	Channel chan<- *NumberLookupResponse
+
+	// MinInterval is the wait after an empty batch, doubled for every next empty batch.
+	// DefaultPollMinInterval when 0 or less.
+	MinInterval time.Duration
+
+	// MaxInterval is the maximum wait between empty batches, at least MinInterval.
+	// DefaultPollMaxInterval when 0 or less.
+	MaxInterval time.Duration
+
+	// ErrorHandler is called with the transient errors of polling (example a timeout or a 503), after
+	// which polling continues with the same backoff as for empty batches. Other errors stop Run.
+	ErrorHandler func(ctx context.Context, err error)
+
+	client *Client
+}
+
+// NewNumberLookupPoller creates a new numberlookup poller calling handler for every result
+func NewNumberLookupPoller(
+	handler func(ctx context.Context, response *NumberLookupResponse) error,
+) *NumberLookupPoller {
+	return DefaultClient.NewNumberLookupPoller(handler)
+}
+
+// NewNumberLookupPoller creates a new numberlookup poller calling handler for every result using the client
+func (c *Client) NewNumberLookupPoller(
+	handler func(ctx context.Context, response *NumberLookupResponse) error,
+) *NumberLookupPoller {
+	return &NumberLookupPoller{
+		Handler:     handler,
+		MinInterval: DefaultPollMinInterval,
+		MaxInterval: DefaultPollMaxInterval,
+		client:      c,
+	}
+}
+
+// Run polls until ctx is done, in which case nil is returned, or until polling fails with an error that
+// is not transient or handling fails. An error is returned right away when there is no Handler or Channel.
+func (p *NumberLookupPoller) Run(ctx context.Context) error {
+	if p.Handler == nil && p.Channel == nil {
+		return errPollerNoHandler
+	}
+
+	var results *NumberLookupPollResults
+	return p.loop().run(
+		ctx,
+		func(ctx context.Context) (*pollResults, int, error) {
+			results = clientOrDefault(p.client).NewNumberLookupPoll()
+			if err := results.StatusContext(ctx); err != nil {
+				return nil, 0, err
+			}
+			if results.Embedded == nil || results.Embedded.Messages == nil {
+				return &results.pollResults, 0, nil
+			}
+			return &results.pollResults, len(*results.Embedded.Messages), nil
+		},
+		func(ctx context.Context, i int) error {
+			response := &(*results.Embedded.Messages)[i]
+			if p.Handler != nil {
+				return p.Handler(ctx, response)
+			}
+			select {
+			case p.Channel <- response:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	)
+}
+
+// loop returns the poll loop using the intervals and ErrorHandler of the poller
+func (p *NumberLookupPoller) loop() *poller {
+	return &poller{minInterval: p.MinInterval, maxInterval: p.MaxInterval, errorHandler: p.ErrorHandler}
+}
@@ -0,0 +1,186 @@
+package twizo_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	twizo "github.com/twizoapi/lib-api-go"
+	. "github.com/twizoapi/lib-api-go/testing"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+func init() {
+	twizo.APIKey = TestAPIKey
+	twizo.RegionCurrent = TestRegion
+}
+
+// registerPollQueue mocks the poll queue of kind, the first poll returns items after
+// which the queue is empty and cancel is called. It returns the amount of deletes.
+func registerPollQueue(kind string, items string, cancel func()) *int {
+	pollURL := fmt.Sprintf("https://%s/%s/%s/poll", twizo.GetHostForRegion(TestRegion), twizo.ClientAPIVersion, kind)
+
+	polls, deletes := 0, 0
+	httpmock.RegisterResponder(
+		http.MethodGet,
+		pollURL,
+		func(req *http.Request) (*http.Response, error) {
+			polls++
+			if polls > 1 {
+				cancel()
+				return httpmock.NewStringResponse(http.StatusOK, `{"batchId":"","count":0}`), nil
+			}
+			return httpmock.NewStringResponse(
+				http.StatusOK,
+				fmt.Sprintf(
+					`{"batchId":"batch","count":2,"_embedded":{"messages":%s},"_links":{"self":{"href":"%s/batch"}}}`,
+					items,
+					pollURL,
+				),
+			), nil
+		},
+	)
+	httpmock.RegisterResponder(
+		http.MethodDelete,
+		pollURL+"/batch",
+		func(req *http.Request) (*http.Response, error) {
+			deletes++
+			return httpmock.NewStringResponse(http.StatusNoContent, ""), nil
+		},
+	)
+
+	return &deletes
+}
+
+func TestSmsPoller(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	deletes := registerPollQueue(
+		"sms",
+		`[{"messageId":"one","recipient":"1"},{"messageId":"two","recipient":"2"}]`,
+		cancel,
+	)
+
+	var handled []string
+	poller := twizo.NewClient(TestAPIKey, TestRegion).NewSmsPoller(
+		func(ctx context.Context, response *twizo.SmsResponse) error {
+			if *deletes != 0 {
+				t.Fatal("Batch deleted before all messages were handled")
+			}
+			handled = append(handled, response.GetMessageID())
+			return nil
+		},
+	)
+	poller.MinInterval = time.Millisecond
+
+	if err := poller.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(handled) != "[one two]" {
+		t.Fatalf("Invalid handled messages expecting [[one two]] got [%v]", handled)
+	}
+	if *deletes != 1 {
+		t.Fatalf("Invalid amount of deletes expecting [1] got [%d]", *deletes)
+	}
+}
+
+func TestSmsPollerHandlerError(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	deletes := registerPollQueue("sms", `[{"messageId":"one","recipient":"1"}]`, cancel)
+
+	failed := errors.New("failed")
+	poller := twizo.NewClient(TestAPIKey, TestRegion).NewSmsPoller(
+		func(ctx context.Context, response *twizo.SmsResponse) error {
+			return failed
+		},
+	)
+
+	if err := poller.Run(ctx); err != failed {
+		t.Fatalf("Invalid error expecting [%v] got [%v]", failed, err)
+	}
+	if *deletes != 0 {
+		t.Fatalf("Invalid amount of deletes expecting [0] got [%d]", *deletes)
+	}
+}
+
+func TestNumberLookupPollerChannel(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	deletes := registerPollQueue(
+		"numberlookup",
+		`[{"messageId":"one","number":"1"},{"messageId":"two","number":"2"}]`,
+		cancel,
+	)
+
+	results := make(chan *twizo.NumberLookupResponse, 2)
+	poller := twizo.NewClient(TestAPIKey, TestRegion).NewNumberLookupPoller(nil)
+	poller.Channel = results
+	poller.MinInterval = time.Millisecond
+
+	if err := poller.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	close(results)
+
+	var numbers []string
+	for response := range results {
+		numbers = append(numbers, response.GetNumber())
+	}
+	if fmt.Sprint(numbers) != "[1 2]" {
+		t.Fatalf("Invalid numbers expecting [[1 2]] got [%v]", numbers)
+	}
+	if *deletes != 1 {
+		t.Fatalf("Invalid amount of deletes expecting [1] got [%d]", *deletes)
+	}
+}
+
+func TestSmsPollerErrors(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	poller := twizo.NewClient(TestAPIKey, TestRegion).NewSmsPoller(nil)
+	if err := poller.Run(context.Background()); err == nil {
+		t.Fatal("Expecting error for poller without handler or channel got [nil]")
+	}
+
+	// the transient error is reported and polling continues until the second error
+	statuses := []int{http.StatusServiceUnavailable, http.StatusUnauthorized}
+	polls := 0
+	httpmock.RegisterResponder(
+		http.MethodGet,
+		fmt.Sprintf("https://%s/%s/sms/poll", twizo.GetHostForRegion(TestRegion), twizo.ClientAPIVersion),
+		func(req *http.Request) (*http.Response, error) {
+			polls++
+			return httpmock.NewStringResponse(statuses[polls-1], ""), nil
+		},
+	)
+
+	var transient []error
+	poller.Handler = func(ctx context.Context, response *twizo.SmsResponse) error { return nil }
+	poller.MinInterval = time.Millisecond
+	poller.ErrorHandler = func(ctx context.Context, err error) {
+		transient = append(transient, err)
+	}
+
+	err := poller.Run(context.Background())
+	if !errors.Is(err, twizo.ErrUnauthorized) {
+		t.Fatalf("Invalid error expecting [%v] got [%v]", twizo.ErrUnauthorized, err)
+	}
+	if len(transient) != 1 || !errors.Is(transient[0], twizo.ErrServer) {
+		t.Fatalf("Invalid transient errors expecting [%v] got %v", twizo.ErrServer, transient)
+	}
+}
@@ -0,0 +1,212 @@
+package twizo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidRecipient is matched by the errors of ParseRecipient, example errors.Is(err, twizo.ErrInvalidRecipient)
+var ErrInvalidRecipient = errors.New("twizo: invalid recipient")
+
+const (
+	// recipientMaxDigits is the maximum length of an international number, including the country calling code
+	recipientMaxDigits = 15
+
+	// recipientMinNationalDigits is the minimum length of the number after the country calling code
+	recipientMinNationalDigits = 4
+)
+
+// RecipientError is the error of ParseRecipient
+type RecipientError struct {
+	Input  string
+	Reason string
+}
+
+// Error returns the error message
+func (e *RecipientError) Error() string {
+	return fmt.Sprintf("invalid recipient [%s]: %s", e.Input, e.Reason)
+}
+
+// Is makes errors.Is(err, ErrInvalidRecipient) match
+func (e *RecipientError) Is(target error) bool {
+	return target == ErrInvalidRecipient
+}
+
+// countryCallingCodes contains the country calling code per ISO 3166-1 alpha-2 country code
+var countryCallingCodes = map[string]string{
+	"AD": "376", "AE": "971", "AF": "93", "AG": "1", "AI": "1", "AL": "355", "AM": "374", "AO": "244",
+	"AR": "54", "AS": "1", "AT": "43", "AU": "61", "AW": "297", "AX": "358", "AZ": "994", "BA": "387",
+	"BB": "1", "BD": "880", "BE": "32", "BF": "226", "BG": "359", "BH": "973", "BI": "257", "BJ": "229",
+	"BL": "590", "BM": "1", "BN": "673", "BO": "591", "BQ": "599", "BR": "55", "BS": "1", "BT": "975",
+	"BW": "267", "BY": "375", "BZ": "501", "CA": "1", "CC": "61", "CD": "243", "CF": "236", "CG": "242",
+	"CH": "41", "CI": "225", "CK": "682", "CL": "56", "CM": "237", "CN": "86", "CO": "57", "CR": "506",
+	"CU": "53", "CV": "238", "CW": "599", "CX": "61", "CY": "357", "CZ": "420", "DE": "49", "DJ": "253",
+	"DK": "45", "DM": "1", "DO": "1", "DZ": "213", "EC": "593", "EE": "372", "EG": "20", "EH": "212",
+	"ER": "291", "ES": "34", "ET": "251", "FI": "358", "FJ": "679", "FK": "500", "FM": "691", "FO": "298",
+	"FR": "33", "GA": "241", "GB": "44", "GD": "1", "GE": "995", "GF": "594", "GG": "44", "GH": "233",
+	"GI": "350", "GL": "299", "GM": "220", "GN": "224", "GP": "590", "GQ": "240", "GR": "30", "GT": "502",
+	"GU": "1", "GW": "245", "GY": "592", "HK": "852", "HN": "504", "HR": "385", "HT": "509", "HU": "36",
+	"ID": "62", "IE": "353", "IL": "972", "IM": "44", "IN": "91", "IO": "246", "IQ": "964", "IR": "98",
+	"IS": "354", "IT": "39", "JE": "44", "JM": "1", "JO": "962", "JP": "81", "KE": "254", "KG": "996",
+	"KH": "855", "KI": "686", "KM": "269", "KN": "1", "KP": "850", "KR": "82", "KW": "965", "KY": "1",
+	"KZ": "7", "LA": "856", "LB": "961", "LC": "1", "LI": "423", "LK": "94", "LR": "231", "LS": "266",
+	"LT": "370", "LU": "352", "LV": "371", "LY": "218", "MA": "212", "MC": "377", "MD": "373", "ME": "382",
+	"MF": "590", "MG": "261", "MH": "692", "MK": "389", "ML": "223", "MM": "95", "MN": "976", "MO": "853",
+	"MP": "1", "MQ": "596", "MR": "222", "MS": "1", "MT": "356", "MU": "230", "MV": "960", "MW": "265",
+	"MX": "52", "MY": "60", "MZ": "258", "NA": "264", "NC": "687", "NE": "227", "NF": "672", "NG": "234",
+	"NI": "505", "NL": "31", "NO": "47", "NP": "977", "NR": "674", "NU": "683", "NZ": "64", "OM": "968",
+	"PA": "507", "PE": "51", "PF": "689", "PG": "675", "PH": "63", "PK": "92", "PL": "48", "PM": "508",
+	"PR": "1", "PS": "970", "PT": "351", "PW": "680", "PY": "595", "QA": "974", "RE": "262", "RO": "40",
+	"RS": "381", "RU": "7", "RW": "250", "SA": "966", "SB": "677", "SC": "248", "SD": "249", "SE": "46",
+	"SG": "65", "SH": "290", "SI": "386", "SJ": "47", "SK": "421", "SL": "232", "SM": "378", "SN": "221",
+	"SO": "252", "SR": "597", "SS": "211", "ST": "239", "SV": "503", "SX": "1", "SY": "963", "SZ": "268",
+	"TC": "1", "TD": "235", "TG": "228", "TH": "66", "TJ": "992", "TK": "690", "TL": "670", "TM": "993",
+	"TN": "216", "TO": "676", "TR": "90", "TT": "1", "TV": "688", "TW": "886", "TZ": "255", "UA": "380",
+	"UG": "256", "US": "1", "UY": "598", "UZ": "998", "VA": "39", "VC": "1", "VE": "58", "VG": "1",
+	"VI": "1", "VN": "84", "VU": "678", "WF": "681", "WS": "685", "XK": "383", "YE": "967", "YT": "262",
+	"ZA": "27", "ZM": "260", "ZW": "263",
+}
+
+// nonGeographicCallingCodes are the calling codes of global services that belong to no country
+var nonGeographicCallingCodes = []string{"800", "808", "870", "878", "881", "882", "883", "888", "979"}
+
+// callingCodes contains all valid country calling codes
+var callingCodes = func() map[string]bool {
+	codes := map[string]bool{}
+	for _, code := range countryCallingCodes {
+		codes[code] = true
+	}
+	for _, code := range nonGeographicCallingCodes {
+		codes[code] = true
+	}
+	return codes
+}()
+
+// sharedCallingCodeCountries contains the country of calling codes shared by multiple countries, a
+// number is attributed to it when the country can not be derived otherwise
+var sharedCallingCodeCountries = map[string]string{
+	"1": "US", "7": "RU", "39": "IT", "44": "GB", "47": "NO", "61": "AU", "212": "MA", "262": "RE",
+	"358": "FI", "590": "GP", "599": "CW",
+}
+
+// callingCodeCountries contains the country per country calling code
+var callingCodeCountries = func() map[string]string {
+	countries := map[string]string{}
+	for country, code := range countryCallingCodes {
+		countries[code] = country
+	}
+	for code, country := range sharedCallingCodeCountries {
+		countries[code] = country
+	}
+	return countries
+}()
+
+// recipientCountry returns the country of the calling code of an international number, empty when the
+// calling code belongs to no country
+func recipientCountry(recipient Recipient) string {
+	for i := 1; i <= 3 && i <= len(recipient); i++ {
+		if country, ok := callingCodeCountries[string(recipient[:i])]; ok {
+			return country
+		}
+	}
+	return ""
+}
+
+// ParseRecipient normalises input to the international format without + that is expected by the api,
+// example "+31 (0)6-12345678" becomes "31612345678". Spaces, dashes, dots, slashes and parentheses are
+// ignored, a (0) after the country calling code is dropped.
+//
+// Numbers starting with + or 00 are international, numbers starting with a single 0 are national and
+// prefixed with the calling code of defaultCountry (ISO 3166-1 alpha-2, example "NL"), other numbers
+// are expected to start with the country calling code already. The country calling code and the
+// length are validated, the returned error matches ErrInvalidRecipient.
+func ParseRecipient(input string, defaultCountry string) (Recipient, error) {
+	invalid := func(reason string, args ...interface{}) (Recipient, error) {
+		return "", &RecipientError{Input: input, Reason: fmt.Sprintf(reason, args...)}
+	}
+
+	number := strings.TrimSpace(input)
+	international := false
+	if strings.HasPrefix(number, "+") {
+		number = number[1:]
+		international = true
+	}
+	// a national trunk prefix written as (0) after the country calling code
+	number = strings.Replace(number, "(0)", "", 1)
+
+	var digits strings.Builder
+	for _, r := range number {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case strings.ContainsRune(" -./()", r):
+		default:
+			return invalid("unexpected character [%c]", r)
+		}
+	}
+	number = digits.String()
+	if number == "" {
+		return invalid("no digits")
+	}
+
+	switch {
+	case international:
+	case strings.HasPrefix(number, "00"):
+		number = number[2:]
+	case strings.HasPrefix(number, "0"):
+		if defaultCountry == "" {
+			return invalid("national number without default country")
+		}
+		code, ok := countryCallingCodes[strings.ToUpper(defaultCountry)]
+		if !ok {
+			return invalid("unknown country [%s]", defaultCountry)
+		}
+		number = code + number[1:]
+	}
+
+	code := ""
+	for i := 1; i <= 3 && i <= len(number); i++ {
+		if callingCodes[number[:i]] {
+			code = number[:i]
+			break
+		}
+	}
+	if code == "" {
+		return invalid("unknown country calling code")
+	}
+	if len(number) > recipientMaxDigits {
+		return invalid("[%d] digits, the maximum is [%d]", len(number), recipientMaxDigits)
+	}
+	if len(number)-len(code) < recipientMinNationalDigits {
+		return invalid("number after country calling code [%s] is too short", code)
+	}
+
+	return Recipient(number), nil
+}
+
+// ParseRecipients parses every input using ParseRecipient, the first error is returned
+func ParseRecipients(inputs []Recipient, defaultCountry string) ([]Recipient, error) {
+	if inputs == nil {
+		return nil, nil
+	}
+	recipients := make([]Recipient, len(inputs))
+	for i, input := range inputs {
+		recipient, err := ParseRecipient(string(input), defaultCountry)
+		if err != nil {
+			return nil, err
+		}
+		recipients[i] = recipient
+	}
+	return recipients, nil
+}
+
+// normaliseRecipient returns number normalised by ParseRecipient, so a number formatted differently by the
+// api still matches, number itself when it can not be parsed
+func normaliseRecipient(number Recipient, defaultCountry string) Recipient {
+	parsed, err := ParseRecipient(string(number), defaultCountry)
+	if err != nil {
+		return number
+	}
+	return parsed
+}
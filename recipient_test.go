@@ -0,0 +1,114 @@
+package twizo_test
+
+import (
+	"errors"
+	"testing"
+
+	twizo "github.com/twizoapi/lib-api-go"
+	. "github.com/twizoapi/lib-api-go/testing"
+)
+
+func init() {
+	twizo.APIKey = TestAPIKey
+	twizo.RegionCurrent = TestRegion
+}
+
+func TestParseRecipient(t *testing.T) {
+	tests := []struct {
+		input   string
+		country string
+		expect  twizo.Recipient
+	}{
+		{"31612345678", "", "31612345678"},
+		{"+31 6 12345678", "", "31612345678"},
+		{"+31 (0)6-1234 5678", "", "31612345678"},
+		{"0031612345678", "", "31612345678"},
+		{"06 12345678", "NL", "31612345678"},
+		{"(020) 123.4567", "nl", "31201234567"},
+		{"+1 (555) 010-9999", "NL", "15550109999"},
+		{"07700 900123", "GB", "447700900123"},
+		{"+800 1234 5678", "", "80012345678"},
+	}
+
+	for _, test := range tests {
+		recipient, err := twizo.ParseRecipient(test.input, test.country)
+		if err != nil {
+			t.Errorf("Unexpected error for [%s]: %v", test.input, err)
+			continue
+		}
+		if recipient != test.expect {
+			t.Errorf("Invalid recipient for [%s] expecting [%s] got [%s]", test.input, test.expect, recipient)
+		}
+	}
+
+	invalid := []struct {
+		input   string
+		country string
+	}{
+		{"", "NL"},
+		{"0612345678", ""},
+		{"0612345678", "XX"},
+		{"+31 6 1234567a", ""},
+		{"999123456", ""},
+		{"+3161", ""},
+		{"+3161234567890123", ""},
+	}
+
+	for _, test := range invalid {
+		if _, err := twizo.ParseRecipient(test.input, test.country); !errors.Is(err, twizo.ErrInvalidRecipient) {
+			t.Errorf("Expecting error for [%s] matching [%v] got [%v]", test.input, twizo.ErrInvalidRecipient, err)
+		}
+	}
+}
+
+func TestRequestsParseRecipients(t *testing.T) {
+	if _, err := twizo.NewSmsRequest([]twizo.Recipient{"0612345678"}, "Body", "Sender"); err == nil {
+		t.Fatal("Expecting error for national number without default country got [nil]")
+	}
+	if _, err := twizo.NewVerificationRequest("12"); !errors.Is(err, twizo.ErrInvalidRecipient) {
+		t.Fatalf("Expecting error matching [%v] got [%v]", twizo.ErrInvalidRecipient, err)
+	}
+	if _, err := twizo.NewBioVoiceRequest("12"); !errors.Is(err, twizo.ErrInvalidRecipient) {
+		t.Fatalf("Expecting error matching [%v] got [%v]", twizo.ErrInvalidRecipient, err)
+	}
+
+	client := twizo.NewClient(TestAPIKey, TestRegion)
+	client.DefaultCountry = "NL"
+
+	request, err := client.NewSmsRequest([]twizo.Recipient{"06-12345678", "+44 7700 900123"}, "Body", "Sender")
+	if err != nil {
+		t.Fatal(err)
+	}
+	recipients := request.GetRecipients()
+	if len(recipients) != 2 || recipients[0] != "31612345678" || recipients[1] != "447700900123" {
+		t.Fatalf("Invalid recipients expecting [[31612345678 447700900123]] got [%v]", recipients)
+	}
+
+	numberLookup, err := client.NewNumberLookupRequest([]twizo.Recipient{"06 12345678"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if numbers := numberLookup.GetNumbers(); len(numbers) != 1 || numbers[0] != "31612345678" {
+		t.Fatalf("Invalid numbers expecting [[31612345678]] got [%v]", numbers)
+	}
+	if err := numberLookup.SetNumbers([]twizo.Recipient{"+0 12345678"}); !errors.Is(err, twizo.ErrInvalidRecipient) {
+		t.Fatalf("Expecting error matching [%v] got [%v]", twizo.ErrInvalidRecipient, err)
+	}
+	if _, err := client.NewNumberLookupRequest([]twizo.Recipient{"12"}); !errors.Is(err, twizo.ErrInvalidRecipient) {
+		t.Fatalf("Expecting error matching [%v] got [%v]", twizo.ErrInvalidRecipient, err)
+	}
+
+	verification, err := client.NewVerificationRequest("+31 6 12345678")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := verification.SetRecipient("06 87654321"); err != nil {
+		t.Fatal(err)
+	}
+	if verification.GetRecipient() != "31687654321" {
+		t.Fatalf("Invalid recipient expecting [31687654321] got [%s]", verification.GetRecipient())
+	}
+	if err := verification.SetRecipient("12"); !errors.Is(err, twizo.ErrInvalidRecipient) {
+		t.Fatalf("Expecting error matching [%v] got [%v]", twizo.ErrInvalidRecipient, err)
+	}
+}
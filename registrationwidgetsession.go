@@ -1,6 +1,7 @@
 package twizo
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"time"
@@ -13,6 +14,7 @@ type RegistrationWidgetSessionRequest struct {
 	backupCodeIdentifier string
 	totpIdentifier       string
 	issuer               string
+	client               *Client
 }
 
 type jsonRegistrationWidgetSessionRequest struct {
@@ -96,6 +98,13 @@ func (request *RegistrationWidgetSessionRequest) SetIssuer(issuer string) {
 
 // Submit wil submit the verification request
 func (request *RegistrationWidgetSessionRequest) Submit() (*RegistrationWidgetSessionResponse, error) {
+	return request.SubmitContext(context.Background())
+}
+
+// SubmitContext submits the registration widget session request using ctx for the call
+func (request *RegistrationWidgetSessionRequest) SubmitContext(
+	ctx context.Context,
+) (*RegistrationWidgetSessionResponse, error) {
 	response := &RegistrationWidgetSessionResponse{}
 	response.allowedTypes = &VerificationTypes{}
 	response.requestedTypes = &VerificationTypes{}
@@ -105,7 +114,9 @@ func (request *RegistrationWidgetSessionRequest) Submit() (*RegistrationWidgetSe
 		return nil, err
 	}
 
-	err = GetClient(RegionCurrent, APIKey).Call(
+	err = clientOrDefault(request.client).call(
+		ctx,
+		EndpointRegistrationWidgetSessionSubmit,
 		http.MethodPost,
 		apiURL,
 		request,
@@ -240,7 +251,12 @@ func (response RegistrationWidgetSessionResponse) String() string {
 
 // NewRegistrationWidgetSessionRequest creates a new widgetsession using a recipient (the only required var)
 func NewRegistrationWidgetSessionRequest() *RegistrationWidgetSessionRequest {
-	registrationWidgetSessionRequest := &RegistrationWidgetSessionRequest{}
+	return DefaultClient.NewRegistrationWidgetSessionRequest()
+}
+
+// NewRegistrationWidgetSessionRequest creates a new registration widgetsession that will use the client
+func (c *Client) NewRegistrationWidgetSessionRequest() *RegistrationWidgetSessionRequest {
+	registrationWidgetSessionRequest := &RegistrationWidgetSessionRequest{client: c}
 	registrationWidgetSessionRequest.allowedTypes = VerificationTypes{}
 	return registrationWidgetSessionRequest
 }
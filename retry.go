@@ -0,0 +1,173 @@
+package twizo
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy describes if and how failed calls are retried. Only transient
+// failures are retried: connection errors, rate limiting (429) and gateway or
+// availability errors (502, 503, 504). Safe methods (GET, HEAD, DELETE) are
+// retried automatically, submits (POST, PUT) are not idempotent and are only
+// retried when RetryPost is set.
+type RetryPolicy struct {
+	// MaxAttempts the total amount of attempts, including the first one
+	MaxAttempts int
+
+	// MinBackoff the backoff before the first retry, it doubles on every
+	// following retry
+	MinBackoff time.Duration
+
+	// MaxBackoff the maximum backoff between two attempts, it also caps the
+	// Retry-After requested by the server
+	MaxBackoff time.Duration
+
+	// RetryPost also retries POST and PUT calls, this might result in
+	// sending the same sms or verification twice
+	RetryPost bool
+}
+
+// DefaultRetryPolicy a sensible retry policy, assign a copy of it to
+// Client.RetryPolicy to enable retrying
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	MinBackoff:  500 * time.Millisecond,
+	MaxBackoff:  10 * time.Second,
+}
+
+// retry returns how long to wait and if the call that failed with err during
+// attempt should be retried at all
+func (p RetryPolicy) retry(ctx context.Context, method string, attempt int, err error) (time.Duration, bool) {
+	if attempt >= p.MaxAttempts || ctx.Err() != nil {
+		return 0, false
+	}
+
+	if !p.isRetryableMethod(method) {
+		return 0, false
+	}
+
+	retryAfter, ok := isRetryableError(err)
+	if !ok {
+		return 0, false
+	}
+
+	if retryAfter > 0 {
+		// the server told us how long to wait, honour it within our maximum
+		if p.MaxBackoff > 0 && retryAfter > p.MaxBackoff {
+			return p.MaxBackoff, true
+		}
+		return retryAfter, true
+	}
+
+	return p.backoff(attempt), true
+}
+
+func (p RetryPolicy) isRetryableMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodDelete:
+		return true
+	case http.MethodPost, http.MethodPut:
+		return p.RetryPost
+	}
+	return false
+}
+
+// backoff calculates the exponential backoff for attempt, with jitter so
+// clients do not retry in lockstep
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	backoff := p.MinBackoff
+	for i := 1; i < attempt && (p.MaxBackoff <= 0 || backoff < p.MaxBackoff); i++ {
+		backoff *= 2
+	}
+	if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+	if backoff <= 0 {
+		return 0
+	}
+
+	// random between half and the full backoff
+	half := backoff / 2
+	return half + time.Duration(rand.Int63n(int64(backoff-half)+1))
+}
+
+func isRetryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// isRetryableError returns true if err, or an error it wraps, is transient,
+// together with the delay requested by the server (if any)
+func isRetryableError(err error) (time.Duration, bool) {
+	var apiError *APIError
+	if errors.As(err, &apiError) {
+		return apiError.RetryAfter(), isRetryableStatus(apiError.Status())
+	}
+	var clientError *ClientError
+	if errors.As(err, &clientError) {
+		return clientError.RetryAfter, isRetryableStatus(clientError.Code)
+	}
+	var urlError *url.Error
+	if errors.As(err, &urlError) {
+		return 0, isRetryableNetError(urlError.Err)
+	}
+
+	return 0, isRetryableNetError(err)
+}
+
+// isRetryableNetError is true for timeouts, temporary errors and refused or
+// reset connections, not for our own cancellation or errors that will happen
+// again like invalid certificates or unsupported schemes
+func isRetryableNetError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return netErr.Timeout() || netErr.Temporary()
+	}
+	return false
+}
+
+// parseRetryAfter parses the Retry-After header, which is either an amount of
+// seconds or a http date
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,252 @@
+package twizo_test
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	twizo "github.com/twizoapi/lib-api-go"
+	. "github.com/twizoapi/lib-api-go/testing"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+func init() {
+	twizo.APIKey = TestAPIKey
+	twizo.RegionCurrent = TestRegion
+}
+
+func newRetryTestClient(retryPost bool) *twizo.Client {
+	client := twizo.NewClient(TestAPIKey, TestRegion)
+	client.RetryPolicy = &twizo.RetryPolicy{
+		MaxAttempts: 3,
+		MinBackoff:  time.Millisecond,
+		MaxBackoff:  2 * time.Millisecond,
+		RetryPost:   retryPost,
+	}
+	return client
+}
+
+// registerFlakyResponder fails failures times with status before answering with body
+func registerFlakyResponder(method string, path string, failures int, status int, body string) *int {
+	calls := 0
+	httpmock.RegisterResponder(
+		method,
+		fmt.Sprintf("https://%s/%s/%s", twizo.GetHostForRegion(TestRegion), twizo.ClientAPIVersion, path),
+		func(req *http.Request) (*http.Response, error) {
+			calls++
+			if calls <= failures {
+				return httpmock.NewStringResponse(status, "unavailable"), nil
+			}
+			resp := httpmock.NewStringResponse(http.StatusOK, body)
+			resp.Header.Set("Content-Type", "application/json")
+			return resp, nil
+		},
+	)
+	return &calls
+}
+
+func TestRetryGetOnServiceUnavailable(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	calls := registerFlakyResponder(
+		http.MethodGet,
+		"wallet/getbalance",
+		2,
+		http.StatusServiceUnavailable,
+		`{"credit":1,"currencyCode":"eur","freeVerifications":0,"wallet":"wallet"}`,
+	)
+
+	response, err := newRetryTestClient(false).BalanceGet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.GetWallet() != "wallet" {
+		t.Fatalf("Invalid wallet expecting [wallet] got [%v]", response.GetWallet())
+	}
+	if *calls != 3 {
+		t.Fatalf("Invalid amount of attempts expecting [3] got [%d]", *calls)
+	}
+}
+
+func TestRetryWrappedError(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	calls := registerFlakyResponder(
+		http.MethodGet,
+		"wallet/getbalance",
+		2,
+		http.StatusServiceUnavailable,
+		`{"credit":1,"currencyCode":"eur","freeVerifications":0,"wallet":"wallet"}`,
+	)
+
+	client := newRetryTestClient(false)
+	client.Middleware = []twizo.Middleware{
+		func(next twizo.CallHandler) twizo.CallHandler {
+			return func(info *twizo.CallInfo) error {
+				if err := next(info); err != nil {
+					return fmt.Errorf("middleware: %w", err)
+				}
+				return nil
+			}
+		},
+	}
+
+	if _, err := client.BalanceGet(); err != nil {
+		t.Fatal(err)
+	}
+	if *calls != 3 {
+		t.Fatalf("Invalid amount of attempts expecting [3] got [%d]", *calls)
+	}
+}
+
+func TestRetryGivesUpAfterMaxAttempts(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	calls := registerFlakyResponder(http.MethodGet, "wallet/getbalance", 5, http.StatusBadGateway, `{}`)
+
+	_, err := newRetryTestClient(false).BalanceGet()
+	clientError, ok := err.(*twizo.ClientError)
+	if !ok {
+		t.Fatalf("Invalid error expecting [twizo.ClientError] got [%#v]", err)
+	}
+	if clientError.Code != http.StatusBadGateway {
+		t.Fatalf("Invalid code expecting [%d] got [%d]", http.StatusBadGateway, clientError.Code)
+	}
+	if *calls != 3 {
+		t.Fatalf("Invalid amount of attempts expecting [3] got [%d]", *calls)
+	}
+}
+
+func TestRetryPostOnlyWhenEnabled(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	calls := registerFlakyResponder(http.MethodPost, "verification/submit", 1, http.StatusServiceUnavailable, `{}`)
+
+	request, err := newRetryTestClient(false).NewVerificationRequest("31600000000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = request.Submit(); err == nil {
+		t.Fatal("Expecting error when not retrying post got [nil]")
+	}
+	if *calls != 1 {
+		t.Fatalf("Invalid amount of attempts expecting [1] got [%d]", *calls)
+	}
+}
+
+func TestRetryAfterIsExposed(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(
+		http.MethodGet,
+		fmt.Sprintf("https://%s/%s/wallet/getbalance", twizo.GetHostForRegion(TestRegion), twizo.ClientAPIVersion),
+		func(req *http.Request) (*http.Response, error) {
+			resp := httpmock.NewStringResponse(
+				http.StatusTooManyRequests,
+				`{"title":"Too Many Requests","status":429,"detail":"Slow down"}`,
+			)
+			resp.Header.Set("Content-Type", "application/problem+json")
+			resp.Header.Set("Retry-After", "7")
+			return resp, nil
+		},
+	)
+
+	// no retry policy, the error is returned as is
+	_, err := twizo.NewClient(TestAPIKey, TestRegion).BalanceGet()
+	apiError, ok := err.(*twizo.APIError)
+	if !ok {
+		t.Fatalf("Invalid error expecting [twizo.APIError] got [%#v]", err)
+	}
+	if apiError.RetryAfter() != 7*time.Second {
+		t.Fatalf("Invalid retry after expecting [7s] got [%v]", apiError.RetryAfter())
+	}
+}
+
+func TestRetryAfterIsCappedAtMaxBackoff(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	calls := 0
+	httpmock.RegisterResponder(
+		http.MethodGet,
+		fmt.Sprintf("https://%s/%s/wallet/getbalance", twizo.GetHostForRegion(TestRegion), twizo.ClientAPIVersion),
+		func(req *http.Request) (*http.Response, error) {
+			calls++
+			if calls == 1 {
+				resp := httpmock.NewStringResponse(http.StatusTooManyRequests, "slow down")
+				resp.Header.Set("Retry-After", "3600")
+				return resp, nil
+			}
+			resp := httpmock.NewStringResponse(http.StatusOK, `{"credit":1}`)
+			resp.Header.Set("Content-Type", "application/json")
+			return resp, nil
+		},
+	)
+
+	start := time.Now()
+	if _, err := newRetryTestClient(false).BalanceGet(); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Invalid backoff expecting the max backoff [2ms] got [%s]", elapsed)
+	}
+	if calls != 2 {
+		t.Fatalf("Invalid amount of attempts expecting [2] got [%d]", calls)
+	}
+}
+
+func TestRetryTransportErrors(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	tests := []struct {
+		name   string
+		err    error
+		expect int
+	}{
+		{
+			"connection refused",
+			&net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)},
+			3,
+		},
+		{
+			"connection reset",
+			&net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)},
+			3,
+		},
+		{"invalid certificate", x509.UnknownAuthorityError{}, 1},
+		{"unsupported scheme", errors.New("unsupported protocol scheme \"ftp\""), 1},
+	}
+
+	for _, test := range tests {
+		calls := 0
+		transportErr := test.err
+		httpmock.RegisterResponder(
+			http.MethodGet,
+			fmt.Sprintf("https://%s/%s/wallet/getbalance", twizo.GetHostForRegion(TestRegion), twizo.ClientAPIVersion),
+			func(req *http.Request) (*http.Response, error) {
+				calls++
+				return nil, transportErr
+			},
+		)
+
+		if _, err := newRetryTestClient(false).BalanceGet(); err == nil {
+			t.Fatalf("Expecting error for [%s] got [nil]", test.name)
+		}
+		if calls != test.expect {
+			t.Errorf("Invalid amount of attempts for [%s] expecting [%d] got [%d]", test.name, test.expect, calls)
+		}
+	}
+}
@@ -1,8 +1,10 @@
 package twizo
 
 import (
+	"context"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -57,6 +59,27 @@ const (
 	SmsStatusCodeUnknown SmsStatusCode = 9
 )
 
+// IsFinal returns true when the status of the message will not change anymore
+func (code SmsStatusCode) IsFinal() bool {
+	switch code {
+	case SmsStatusCodeDelivered,
+		SmsStatusCodeRejected,
+		SmsStatusCodeExpired,
+		SmsStatusCodeUndelivered,
+		SmsStatusCodeDeleted:
+		return true
+	}
+	return false
+}
+
+const (
+	// SmsValidityMin is the minimum validity of an SMS
+	SmsValidityMin = 1 * time.Second
+
+	// SmsValidityMax is the maximum validity of an SMS
+	SmsValidityMax = 72 * time.Hour
+)
+
 // SmsRequest is used to send an sms request
 type SmsRequest struct {
 	recipients        []Recipient
@@ -66,13 +89,14 @@ type SmsRequest struct {
 	senderTon         int // posivive small int
 	senderNpi         int // positive small int
 	pid               int
-	scheduledDelivery string
+	scheduledDelivery time.Time
 	tag               string
-	validity          string // duration.Seconds
+	validity          time.Duration
 	resultType        ResultType
 	callbackURL       *url.URL // only relevant for SmsResultTypeCallback | SmsResultTYpeCallbackPollling
 	dcs               int      // relevant for advanced, 0-255
 	udh               *string  // relevant for advanced, hex
+	client            *Client
 }
 
 type jsonSmsRequest struct {
@@ -84,9 +108,9 @@ type jsonSmsRequest struct {
 	Pid               int         `json:"pid,omitempty"`
 	ScheduledDelivery string      `json:"scheduledDelivery,omitempty"`
 	Tag               string      `json:"tag,omitempty"`
-	Validity          string      `json:"validity,omitempty"`
+	Validity          int         `json:"validity,omitempty"`
 	ResultType        ResultType  `json:"resultType"`
-	CallbackURL       *url.URL    `json:"callbackUrl,omitempty"`
+	CallbackURL       string      `json:"callbackUrl,omitempty"`
 	Dcs               int         `json:"dcs,omitempty"`
 	Udh               *string     `json:"udh,omitempty"`
 }
@@ -94,21 +118,26 @@ type jsonSmsRequest struct {
 // MarshalJSON is used to convert SmsRequest to json
 func (request *SmsRequest) MarshalJSON() ([]byte, error) {
 	jsonRequest := jsonSmsRequest{
-		Recipients:        request.recipients,
-		Body:              string(request.body),
-		Sender:            request.sender,
-		SenderTon:         request.senderTon,
-		SenderNpi:         request.senderNpi,
-		Pid:               request.pid,
-		ScheduledDelivery: request.scheduledDelivery,
-		Validity:          request.validity,
-		ResultType:        request.resultType,
-		Tag:               request.tag,
+		Recipients: request.recipients,
+		Body:       string(request.body),
+		Sender:     request.sender,
+		SenderTon:  request.senderTon,
+		SenderNpi:  request.senderNpi,
+		Pid:        request.pid,
+		Validity:   int(request.validity / time.Second),
+		ResultType: request.resultType,
+		Tag:        request.tag,
+	}
+
+	if !request.scheduledDelivery.IsZero() {
+		jsonRequest.ScheduledDelivery = request.scheduledDelivery.Format(time.RFC3339)
 	}
 
 	// set the callback url if we need one, it still might be empty
 	if request.resultType == ResultTypeCallback || request.resultType == ResultTypeCallbackPolling {
-		jsonRequest.CallbackURL = request.callbackURL
+		if request.callbackURL != nil {
+			jsonRequest.CallbackURL = request.callbackURL.String()
+		}
 	}
 
 	if request.submitType == SmsSubmitTypeAdvanced {
@@ -188,13 +217,22 @@ func (request SmsRequest) GetBodyAsByteArr() ([]byte, error) {
 	return request.body, nil
 }
 
-// SetRecipients set the recipients
+// SetRecipients set the recipients, they are normalised using ParseRecipient
 func (request *SmsRequest) SetRecipients(recipients []Recipient) error {
+	recipients, err := ParseRecipients(recipients, clientOrDefault(request.client).getDefaultCountry())
+	if err != nil {
+		return err
+	}
 	request.recipients = recipients
 
 	return nil
 }
 
+// GetRecipients returns the recipients
+func (request SmsRequest) GetRecipients() []Recipient {
+	return request.recipients
+}
+
 // SetResultType set the resultType
 func (request *SmsRequest) SetResultType(resultType ResultType) error {
 	request.resultType = resultType
@@ -240,26 +278,38 @@ func (request SmsRequest) GetPid() int {
 	return request.pid
 }
 
-// SetScheduledDelivery this will set the delivery schedule (ie when the SMS will be sent), this should must be
-// in ISO-8601 format
-func (request *SmsRequest) SetScheduledDelivery(sd string) {
-	request.scheduledDelivery = sd
+// SetScheduledDelivery sets when the SMS will be sent, it should be in the future. The zero time
+// sends the SMS immediately.
+func (request *SmsRequest) SetScheduledDelivery(scheduledDelivery time.Time) error {
+	if !scheduledDelivery.IsZero() && !scheduledDelivery.After(time.Now()) {
+		return fmt.Errorf("scheduled delivery [%s] should be in the future", scheduledDelivery.Format(time.RFC3339))
+	}
+	request.scheduledDelivery = scheduledDelivery
+
+	return nil
 }
 
-// GetScheduledDelivery gets the scheduled delivery
-func (request SmsRequest) GetScheduledDelivery() string {
+// GetScheduledDelivery gets the scheduled delivery, the zero time when not scheduled
+func (request SmsRequest) GetScheduledDelivery() time.Time {
 	return request.scheduledDelivery
 }
 
-// SetValidity sets the validity of the message, this value is the amount of seconds that the message is valid
-// after sending the SMS, if this expires the message will be expire and no more attempts will be made
-// todo: validity should be an integer
-func (request *SmsRequest) SetValidity(validity string) {
+// SetValidity sets how long the SMS is valid after sending it, if this expires the message will
+// expire and no more attempts will be made. It should be whole seconds between SmsValidityMin and
+// SmsValidityMax, 0 uses the default of the api.
+func (request *SmsRequest) SetValidity(validity time.Duration) error {
+	if validity != 0 {
+		if err := validateValidity(validity, SmsValidityMin, SmsValidityMax); err != nil {
+			return err
+		}
+	}
 	request.validity = validity
+
+	return nil
 }
 
-// GetValidity gets the validity of the message.
-func (request SmsRequest) GetValidity() string {
+// GetValidity gets the validity of the message, 0 when not set
+func (request SmsRequest) GetValidity() time.Duration {
 	return request.validity
 }
 
@@ -298,6 +348,11 @@ func (request SmsRequest) GetUdh() *string {
 
 // Submit the message
 func (request *SmsRequest) Submit() (*SmsResponses, error) {
+	return request.SubmitContext(context.Background())
+}
+
+// SubmitContext submits the message, ctx controls cancellation and the deadline of the call
+func (request *SmsRequest) SubmitContext(ctx context.Context) (*SmsResponses, error) {
 	response := &SmsResponses{}
 
 	var apiURL *url.URL
@@ -313,7 +368,10 @@ func (request *SmsRequest) Submit() (*SmsResponses, error) {
 		return nil, err
 	}
 
-	err = GetClient(RegionCurrent, APIKey).Call(
+	client := clientOrDefault(request.client)
+	err = client.call(
+		ctx,
+		EndpointSmsSubmit,
 		http.MethodPost,
 		apiURL,
 		request,
@@ -323,6 +381,7 @@ func (request *SmsRequest) Submit() (*SmsResponses, error) {
 	if err != nil {
 		return nil, err
 	}
+	response.setClient(client)
 
 	return response, nil
 }
@@ -339,11 +398,11 @@ type SmsResponse struct {
 	pid                    *string
 	reasonCode             *int
 	recipient              Recipient
-	resultTimestamp        *string
+	resultTimestamp        *time.Time
 	resultType             int
 	salesPrice             *float32
 	salesPriceCurrencyCode *string
-	scheduledDelivery      *string
+	scheduledDelivery      *time.Time
 	sender                 string
 	senderNpi              int
 	senderTon              int
@@ -354,12 +413,13 @@ type SmsResponse struct {
 	validity               int
 	validUntilDateTime     time.Time
 	links                  HATEOASLinks
+	client                 *Client
 }
 
 type jsonSmsResponse struct {
 	ApplicationTag         string        `json:"applicationTag,omitempty"`
 	Body                   string        `json:"body"`
-	CallbackURL            *url.URL      `json:"callbackUrl,omitempty"`
+	CallbackURL            *string       `json:"callbackUrl,omitempty"`
 	CreateDateTime         time.Time     `json:"createdDateTime,omitempty"`
 	Dcs                    int           `json:"dcs,omitempty"`
 	MessageID              string        `json:"messageId"`
@@ -400,7 +460,6 @@ func (response *SmsResponse) copyFrom(j *jsonSmsResponse) error {
 	var err error // default err is nil
 
 	response.applicationTag = j.ApplicationTag
-	response.callbackURL = j.CallbackURL
 	response.createDateTime = j.CreateDateTime
 	response.dcs = j.Dcs
 	response.messageID = j.MessageID
@@ -408,12 +467,10 @@ func (response *SmsResponse) copyFrom(j *jsonSmsResponse) error {
 	response.pid = j.Pid
 	response.reasonCode = j.ReasonCode
 	response.recipient = j.Recipient
-	response.resultTimestamp = j.ResultTimestamp
 	response.tag = j.Tag
 	response.resultType = j.ResultType
 	response.salesPrice = j.SalesPrice
 	response.salesPriceCurrencyCode = j.SalesPriceCurrencyCode
-	response.scheduledDelivery = j.ScheduledDelivery
 	response.sender = j.Sender
 	response.senderNpi = j.SenderNpi
 	response.senderTon = j.SenderTon
@@ -424,6 +481,22 @@ func (response *SmsResponse) copyFrom(j *jsonSmsResponse) error {
 	response.validUntilDateTime = j.ValidUntilDateTime
 	response.links = j.Links
 
+	if j.CallbackURL != nil {
+		response.callbackURL, err = url.Parse(*j.CallbackURL)
+		if err != nil {
+			return err
+		}
+	}
+
+	response.resultTimestamp, err = parseTimestamp(j.ResultTimestamp)
+	if err != nil {
+		return err
+	}
+	response.scheduledDelivery, err = parseTimestamp(j.ScheduledDelivery)
+	if err != nil {
+		return err
+	}
+
 	if response.IsBinary() {
 		response.body, err = hex.DecodeString(j.Body)
 	} else {
@@ -493,8 +566,8 @@ func (response SmsResponse) GetRecipient() Recipient {
 	return response.recipient
 }
 
-// GetResultTimestamp returns the timestamp of the result
-func (response SmsResponse) GetResultTimestamp() *string {
+// GetResultTimestamp returns the timestamp of the result, nil when there is no result yet
+func (response SmsResponse) GetResultTimestamp() *time.Time {
 	return response.resultTimestamp
 }
 
@@ -513,8 +586,8 @@ func (response SmsResponse) GetSalesPriceCurrencyCode() *string {
 	return response.salesPriceCurrencyCode
 }
 
-// GetScheduledDelivery returns the scheduled delivery string if set
-func (response SmsResponse) GetScheduledDelivery() *string {
+// GetScheduledDelivery returns the scheduled delivery if set
+func (response SmsResponse) GetScheduledDelivery() *time.Time {
 	return response.scheduledDelivery
 }
 
@@ -565,9 +638,16 @@ func (response SmsResponse) GetValidUntilDateTime() time.Time {
 
 // Status gets the status of one message
 func (response *SmsResponse) Status() error {
-	newResponse := &SmsResponse{}
+	return response.StatusContext(context.Background())
+}
+
+// StatusContext gets the status of one message using ctx for the call
+func (response *SmsResponse) StatusContext(ctx context.Context) error {
+	newResponse := &SmsResponse{client: response.client}
 
-	err := GetClient(RegionCurrent, APIKey).Call(
+	err := clientOrDefault(response.client).call(
+		ctx,
+		EndpointSmsStatus,
 		http.MethodGet,
 		&response.links.Self.Href,
 		nil,
@@ -595,6 +675,9 @@ type jsonSmsResponses struct {
 // SmsResponses response contains multiple SmsMessages
 type SmsResponses struct {
 	Responses *[]SmsResponse
+
+	// Concurrency is the maximum amount of concurrent calls made by Status, DefaultStatusConcurrency when 0
+	Concurrency int
 }
 
 // UnmarshalJSON unmarshals the responses to JSON
@@ -611,19 +694,32 @@ func (r *SmsResponses) UnmarshalJSON(j []byte) error {
 	return nil
 }
 
+func (r *SmsResponses) setClient(c *Client) {
+	if r.Responses == nil {
+		return
+	}
+	for i := range *r.Responses {
+		(*r.Responses)[i].client = c
+	}
+}
+
 // Status requests the status of a response
 func (r *SmsResponses) Status() error {
-	newItems := []SmsResponse{}
-	for _, item := range *r.Responses {
-		err := item.Status()
-		if err != nil {
-			return err
-		}
-		newItems = append(newItems, item)
+	return r.StatusContext(context.Background())
+}
+
+// StatusContext requests the status of all responses using ctx for the calls, at most Concurrency at
+// a time. The responses are updated in place, when some of the calls fail a *StatusError is returned
+// containing the failed message ids.
+func (r *SmsResponses) StatusContext(ctx context.Context) error {
+	if r.Responses == nil {
+		return nil
 	}
-	// all went well update Items
-	r.Responses = &newItems
-	return nil
+	items := *r.Responses
+
+	return statusAll(ctx, len(items), r.Concurrency, func(ctx context.Context, i int) (string, error) {
+		return items[i].GetMessageID(), items[i].StatusContext(ctx)
+	})
 }
 
 // GetItems retrieve all messages for the response
@@ -650,15 +746,36 @@ func (p SmsPollResults) GetItems() []SmsResponse {
 	return *p.Embedded.Messages
 }
 
+func (p *SmsPollResults) setClient(c *Client) {
+	p.client = c
+	if p.Embedded == nil || p.Embedded.Messages == nil {
+		return
+	}
+	for i := range *p.Embedded.Messages {
+		(*p.Embedded.Messages)[i].client = c
+	}
+}
+
 // NewSmsPoll creates a new smspollresult
 func NewSmsPoll() *SmsPollResults {
+	return DefaultClient.NewSmsPoll()
+}
+
+// NewSmsPoll creates a new smspollresult using the client
+func (c *Client) NewSmsPoll() *SmsPollResults {
 	params := &SmsPollResults{}
+	params.client = c
 	return params
 }
 
 // SmsPollStatus [todo: refactor]
 func SmsPollStatus() (*SmsPollResults, error) {
-	request := NewSmsPoll()
+	return DefaultClient.SmsPollStatus()
+}
+
+// SmsPollStatus retrieves the sms poll results using the client
+func (c *Client) SmsPollStatus() (*SmsPollResults, error) {
+	request := c.NewSmsPoll()
 	err := request.Status()
 
 	return request, err
@@ -666,13 +783,20 @@ func SmsPollStatus() (*SmsPollResults, error) {
 
 // Status get the status of a sms poll result
 func (p *SmsPollResults) Status() error {
+	return p.StatusContext(context.Background())
+}
+
+// StatusContext gets the status of a sms poll result using ctx for the call
+func (p *SmsPollResults) StatusContext(ctx context.Context) error {
 	apiURL, err := p.getURL()
 	if err != nil {
 		return err
 	}
-	resp := NewSmsPoll()
-	err = p.status(apiURL, resp)
+	resp := &SmsPollResults{}
+	err = p.status(ctx, EndpointSmsPoll, apiURL, resp)
 	if err == nil {
+		resp.setClient(p.client)
+		resp.deleteEndpoint = EndpointSmsPollDelete
 		*p = *resp
 	}
 	return err
@@ -680,9 +804,17 @@ func (p *SmsPollResults) Status() error {
 
 // NewSmsRequest creates a new smsrequest struct
 func NewSmsRequest(recipients []Recipient, body interface{}, sender string) (*SmsRequest, error) {
+	return DefaultClient.NewSmsRequest(recipients, body, sender)
+}
+
+// NewSmsRequest creates a new smsrequest struct that will be submitted using the client
+func (c *Client) NewSmsRequest(recipients []Recipient, body interface{}, sender string) (*SmsRequest, error) {
 	params := &SmsRequest{
-		recipients: recipients,
 		submitType: SmsSubmitTypeSimple,
+		client:     c,
+	}
+	if err := params.SetRecipients(recipients); err != nil {
+		return nil, err
 	}
 	if err := params.SetSender(sender); err != nil {
 		return nil, err
@@ -696,6 +828,16 @@ func NewSmsRequest(recipients []Recipient, body interface{}, sender string) (*Sm
 
 // SmsStatus retrieves the status of a message by ID
 func SmsStatus(messageID string) (*SmsResponse, error) {
+	return DefaultClient.SmsStatus(messageID)
+}
+
+// SmsStatus retrieves the status of a message by ID using the client
+func (c *Client) SmsStatus(messageID string) (*SmsResponse, error) {
+	return c.SmsStatusContext(context.Background(), messageID)
+}
+
+// SmsStatusContext retrieves the status of a message by ID using ctx for the call
+func (c *Client) SmsStatusContext(ctx context.Context, messageID string) (*SmsResponse, error) {
 	apiURL, err := GetURLFor(fmt.Sprintf("sms/submit/%s", url.PathEscape(messageID)))
 	if err != nil {
 		return nil, err
@@ -704,14 +846,13 @@ func SmsStatus(messageID string) (*SmsResponse, error) {
 	smsResponse := &SmsResponse{
 		messageID: messageID,
 		links:     createSelfLinks(apiURL),
+		client:    c,
 	}
 
-	err = smsResponse.Status()
-	// were we able to find it ?
-	if apiError, ok := err.(APIError); ok {
-		if apiError.Status() == http.StatusNotFound {
-			return nil, nil
-		}
+	err = smsResponse.StatusContext(ctx)
+	// were we able to find it ? if not the error matches ErrNotFound
+	if errors.Is(err, ErrNotFound) {
+		return nil, err
 	}
 
 	return smsResponse, err
@@ -719,11 +860,16 @@ func SmsStatus(messageID string) (*SmsResponse, error) {
 
 // SmsSubmit submits a message to recipients
 func SmsSubmit(recipients interface{}, body interface{}, sender string) (*SmsResponses, error) {
+	return DefaultClient.SmsSubmit(recipients, body, sender)
+}
+
+// SmsSubmit submits a message to recipients using the client
+func (c *Client) SmsSubmit(recipients interface{}, body interface{}, sender string) (*SmsResponses, error) {
 	r, err := convertRecipients(recipients)
 	if err != nil {
 		return nil, err
 	}
-	sms, err := NewSmsRequest(r, body, sender)
+	sms, err := c.NewSmsRequest(r, body, sender)
 	if err != nil {
 		return nil, err
 	}
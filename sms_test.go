@@ -9,6 +9,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
 	"gopkg.in/jarcoal/httpmock.v1"
 )
@@ -30,7 +33,7 @@ func TestSmsInvalidJsonResponse(t *testing.T) {
 }
 
 func TestSmsNew(t *testing.T) {
-	smsRequest, err := twizo.NewSmsRequest([]twizo.Recipient{twizo.Recipient("0000000000")}, "Message", "Sender")
+	smsRequest, err := twizo.NewSmsRequest([]twizo.Recipient{twizo.Recipient("31600000000")}, "Message", "Sender")
 	if err != nil {
 		t.Fatal(err)
 		return
@@ -43,6 +46,26 @@ func TestSmsNew(t *testing.T) {
 	}
 }
 
+func TestSmsCallbackURL(t *testing.T) {
+	smsRequest, err := twizo.NewSmsRequest([]twizo.Recipient{twizo.Recipient("31600000000")}, "Message", "Sender")
+	if err != nil {
+		t.Fatal(err)
+	}
+	callbackURL, _ := url.Parse("https://example.com/callback")
+	if err := smsRequest.SetResultType(twizo.ResultTypeCallback); err != nil {
+		t.Fatal(err)
+	}
+	smsRequest.SetCallbackURL(callbackURL)
+
+	j, err := json.Marshal(smsRequest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(j), `"callbackUrl":"https://example.com/callback"`) {
+		t.Fatalf("Invalid callbackUrl expecting [https://example.com/callback] got [%s]", j)
+	}
+}
+
 func TestSmsSubmit(t *testing.T) {
 	httpmock.Activate()
 	defer httpmock.DeactivateAndReset()
@@ -138,3 +161,69 @@ func TestSmsBinary(t *testing.T) {
 		t.Fatal("SmsRequest should not be binary it is")
 	}
 }
+
+func TestSmsScheduledDeliveryAndValidity(t *testing.T) {
+	smsRequest, err := twizo.NewSmsRequest([]twizo.Recipient{twizo.Recipient("31600000000")}, "Message", "Sender")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scheduledDelivery := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	if err := smsRequest.SetScheduledDelivery(scheduledDelivery); err != nil {
+		t.Fatal(err)
+	}
+	if err := smsRequest.SetValidity(2 * time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	j, err := json.Marshal(smsRequest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var data struct {
+		ScheduledDelivery string `json:"scheduledDelivery"`
+		Validity          int    `json:"validity"`
+	}
+	if err := json.Unmarshal(j, &data); err != nil {
+		t.Fatal(err)
+	}
+	if data.ScheduledDelivery != scheduledDelivery.Format(time.RFC3339) {
+		t.Fatalf(
+			"Invalid scheduledDelivery expecting [%s] got [%s]",
+			scheduledDelivery.Format(time.RFC3339),
+			data.ScheduledDelivery,
+		)
+	}
+	if data.Validity != 7200 {
+		t.Fatalf("Invalid validity expecting [7200] got [%d]", data.Validity)
+	}
+
+	if err := smsRequest.SetScheduledDelivery(time.Now().Add(-time.Minute)); err == nil {
+		t.Fatal("Expecting error for scheduled delivery in the past got [nil]")
+	}
+	for _, validity := range []time.Duration{1500 * time.Millisecond, -time.Second, twizo.SmsValidityMax + time.Second} {
+		if err := smsRequest.SetValidity(validity); err == nil {
+			t.Fatalf("Expecting error for validity [%s] got [nil]", validity)
+		}
+	}
+	if smsRequest.GetValidity() != 2*time.Hour {
+		t.Fatalf("Invalid validity expecting [2h0m0s] got [%s]", smsRequest.GetValidity())
+	}
+
+	response := &twizo.SmsResponse{}
+	err = json.Unmarshal(
+		[]byte(`{"resultTimestamp":"2017-03-16T12:00:00+00:00","scheduledDelivery":"2017-03-16T11:00:00+01:00"}`),
+		response,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := time.Date(2017, 3, 16, 12, 0, 0, 0, time.UTC)
+	if response.GetResultTimestamp() == nil || !response.GetResultTimestamp().Equal(expect) {
+		t.Fatalf("Invalid resultTimestamp expecting [%s] got [%v]", expect, response.GetResultTimestamp())
+	}
+	expect = expect.Add(-2 * time.Hour)
+	if response.GetScheduledDelivery() == nil || !response.GetScheduledDelivery().Equal(expect) {
+		t.Fatalf("Invalid scheduledDelivery expecting [%s] got [%v]", expect, response.GetScheduledDelivery())
+	}
+}
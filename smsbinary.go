@@ -0,0 +1,246 @@
+package twizo
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+const (
+	// SmsPortWapPush the destination port of wap push messages
+	SmsPortWapPush = 2948
+
+	// SmsPortWapPushSource the source port of wap push messages
+	SmsPortWapPushSource = 9200
+
+	// SmsPortVCard the destination port of vCard messages
+	SmsPortVCard = 9204
+
+	// SmsPortVCalendar the destination port of vCalendar messages
+	SmsPortVCalendar = 9205
+)
+
+const (
+	// smsDcsClass0 is the message class 0 (flash) bit of the general data coding group
+	smsDcsClass0 = 0x10
+
+	// smsDcs8Bit is the dcs of 8 bit data without a message class
+	smsDcs8Bit = 0x04
+)
+
+// the wap push wsp headers, transaction id 0x01, pdu type push (0x06) and the content type
+var (
+	wapPushSIHeader = []byte{0x01, 0x06, 0x01, 0xAE} // application/vnd.wap.sic
+	wapPushSLHeader = []byte{0x01, 0x06, 0x01, 0xB0} // application/vnd.wap.slc
+)
+
+// the si and sl href attribute start tokens per url prefix, longest prefix first
+var (
+	wapPushSIHref = []wbxmlHref{
+		{"https://www.", 0x0F},
+		{"http://www.", 0x0D},
+		{"https://", 0x0E},
+		{"http://", 0x0C},
+		{"", 0x0B},
+	}
+	wapPushSLHref = []wbxmlHref{
+		{"https://www.", 0x0C},
+		{"http://www.", 0x0A},
+		{"https://", 0x0B},
+		{"http://", 0x09},
+		{"", 0x08},
+	}
+)
+
+type wbxmlHref struct {
+	prefix string
+	token  byte
+}
+
+// NewFlashSmsRequest creates a new flash (class 0) sms request, the message is shown immediately and not stored
+func NewFlashSmsRequest(recipients []Recipient, body string, sender string) (*SmsRequest, error) {
+	return DefaultClient.NewFlashSmsRequest(recipients, body, sender)
+}
+
+// NewFlashSmsRequest creates a new flash (class 0) sms request that will be submitted using the client
+func (c *Client) NewFlashSmsRequest(recipients []Recipient, body string, sender string) (*SmsRequest, error) {
+	request, err := c.newAdvancedSmsRequest(recipients, []byte(body), sender, smsDcsClass0, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.dcs = request.dcsFor(DetectSmsEncoding(body))
+
+	return request, nil
+}
+
+// NewPortSmsRequest creates a new 8 bit sms request addressed to an application port
+func NewPortSmsRequest(
+	recipients []Recipient,
+	body []byte,
+	sender string,
+	destinationPort uint16,
+	sourcePort uint16,
+) (*SmsRequest, error) {
+	return DefaultClient.NewPortSmsRequest(recipients, body, sender, destinationPort, sourcePort)
+}
+
+// NewPortSmsRequest creates a new 8 bit sms request addressed to an application port that will be
+// submitted using the client
+func (c *Client) NewPortSmsRequest(
+	recipients []Recipient,
+	body []byte,
+	sender string,
+	destinationPort uint16,
+	sourcePort uint16,
+) (*SmsRequest, error) {
+	// application port addressing 16 bit address information element
+	udh := []byte{
+		0x05,
+		0x04,
+		byte(destinationPort >> 8),
+		byte(destinationPort),
+		byte(sourcePort >> 8),
+		byte(sourcePort),
+	}
+
+	return c.newAdvancedSmsRequest(recipients, body, sender, smsDcs8Bit, udh)
+}
+
+// NewWapPushSIRequest creates a new wap push service indication, showing text and a link to href
+func NewWapPushSIRequest(recipients []Recipient, href string, text string, sender string) (*SmsRequest, error) {
+	return DefaultClient.NewWapPushSIRequest(recipients, href, text, sender)
+}
+
+// NewWapPushSIRequest creates a new wap push service indication, showing text and a link to href, that
+// will be submitted using the client
+func (c *Client) NewWapPushSIRequest(
+	recipients []Recipient,
+	href string,
+	text string,
+	sender string,
+) (*SmsRequest, error) {
+	if err := validateWbxmlString(href, text); err != nil {
+		return nil, err
+	}
+
+	body := bytes.NewBuffer(append([]byte{}, wapPushSIHeader...))
+	// wbxml 1.2, si 1.0, utf-8, no string table
+	body.Write([]byte{0x02, 0x05, 0x6A, 0x00})
+	// <si><indication href="..." action="signal-medium">
+	body.Write([]byte{0x45, 0xC6})
+	writeWbxmlHref(body, wapPushSIHref, href)
+	body.Write([]byte{0x07, 0x01})
+	// text</indication></si>
+	writeWbxmlString(body, text)
+	body.Write([]byte{0x01, 0x01})
+
+	return c.NewPortSmsRequest(recipients, body.Bytes(), sender, SmsPortWapPush, SmsPortWapPushSource)
+}
+
+// NewWapPushSLRequest creates a new wap push service loading, the phone opens href
+func NewWapPushSLRequest(recipients []Recipient, href string, sender string) (*SmsRequest, error) {
+	return DefaultClient.NewWapPushSLRequest(recipients, href, sender)
+}
+
+// NewWapPushSLRequest creates a new wap push service loading, the phone opens href, that will be
+// submitted using the client
+func (c *Client) NewWapPushSLRequest(recipients []Recipient, href string, sender string) (*SmsRequest, error) {
+	if err := validateWbxmlString(href); err != nil {
+		return nil, err
+	}
+
+	body := bytes.NewBuffer(append([]byte{}, wapPushSLHeader...))
+	// wbxml 1.2, sl 1.0, utf-8, no string table
+	body.Write([]byte{0x02, 0x06, 0x6A, 0x00})
+	// <sl href="..." action="execute-low"/>
+	body.WriteByte(0x85)
+	writeWbxmlHref(body, wapPushSLHref, href)
+	body.Write([]byte{0x05, 0x01})
+
+	return c.NewPortSmsRequest(recipients, body.Bytes(), sender, SmsPortWapPush, SmsPortWapPushSource)
+}
+
+// NewVCardSmsRequest creates a new sms request sending the vCard to the address book of the phone
+func NewVCardSmsRequest(recipients []Recipient, vCard string, sender string) (*SmsRequest, error) {
+	return DefaultClient.NewVCardSmsRequest(recipients, vCard, sender)
+}
+
+// NewVCardSmsRequest creates a new sms request sending the vCard to the address book of the phone that will be
+// submitted using the client
+func (c *Client) NewVCardSmsRequest(recipients []Recipient, vCard string, sender string) (*SmsRequest, error) {
+	if !strings.HasPrefix(strings.ToUpper(vCard), "BEGIN:VCARD") {
+		return nil, errors.New("vCard should start with BEGIN:VCARD")
+	}
+
+	return c.NewPortSmsRequest(recipients, []byte(vCard), sender, SmsPortVCard, 0)
+}
+
+// NewVCalendarSmsRequest creates a new sms request sending the vCalendar to the calendar of the phone
+func NewVCalendarSmsRequest(recipients []Recipient, vCalendar string, sender string) (*SmsRequest, error) {
+	return DefaultClient.NewVCalendarSmsRequest(recipients, vCalendar, sender)
+}
+
+// NewVCalendarSmsRequest creates a new sms request sending the vCalendar to the calendar of the phone that
+// will be submitted using the client
+func (c *Client) NewVCalendarSmsRequest(
+	recipients []Recipient,
+	vCalendar string,
+	sender string,
+) (*SmsRequest, error) {
+	if !strings.HasPrefix(strings.ToUpper(vCalendar), "BEGIN:VCALENDAR") {
+		return nil, errors.New("vCalendar should start with BEGIN:VCALENDAR")
+	}
+
+	return c.NewPortSmsRequest(recipients, []byte(vCalendar), sender, SmsPortVCalendar, 0)
+}
+
+// newAdvancedSmsRequest creates an advanced sms request with dcs and the udh information elements
+func (c *Client) newAdvancedSmsRequest(
+	recipients []Recipient,
+	body []byte,
+	sender string,
+	dcs int,
+	elements []byte,
+) (*SmsRequest, error) {
+	request, err := c.NewSmsRequest(recipients, body, sender)
+	if err != nil {
+		return nil, err
+	}
+	request.submitType = SmsSubmitTypeAdvanced
+	request.dcs = dcs
+
+	if len(elements) > 0 {
+		udh := strings.ToUpper(hex.EncodeToString(append([]byte{byte(len(elements))}, elements...)))
+		request.udh = &udh
+	}
+
+	return request, nil
+}
+
+func validateWbxmlString(values ...string) error {
+	for _, value := range values {
+		if strings.ContainsRune(value, 0) {
+			return errors.New("wap push values can not contain a null character")
+		}
+	}
+	return nil
+}
+
+// writeWbxmlHref writes the href attribute, using the token for the url prefix
+func writeWbxmlHref(body *bytes.Buffer, tokens []wbxmlHref, href string) {
+	for _, token := range tokens {
+		if strings.HasPrefix(href, token.prefix) {
+			body.WriteByte(token.token)
+			writeWbxmlString(body, strings.TrimPrefix(href, token.prefix))
+			return
+		}
+	}
+}
+
+// writeWbxmlString writes value as inline string
+func writeWbxmlString(body *bytes.Buffer, value string) {
+	body.WriteByte(0x03)
+	body.WriteString(value)
+	body.WriteByte(0x00)
+}
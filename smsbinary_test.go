@@ -0,0 +1,127 @@
+package twizo_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	twizo "github.com/twizoapi/lib-api-go"
+	. "github.com/twizoapi/lib-api-go/testing"
+)
+
+func init() {
+	twizo.APIKey = TestAPIKey
+	twizo.RegionCurrent = TestRegion
+}
+
+func TestFlashSmsRequest(t *testing.T) {
+	tests := map[string]int{
+		"Flash":   0x10,
+		"Flash ç": 0x18,
+	}
+
+	for body, dcs := range tests {
+		request, err := twizo.NewFlashSmsRequest([]twizo.Recipient{"31600000000"}, body, "Sender")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if request.GetDcs() != dcs {
+			t.Fatalf("Invalid dcs for [%s] expecting [%d] got [%d]", body, dcs, request.GetDcs())
+		}
+		if request.IsBinary() {
+			t.Fatalf("Flash sms [%s] should not be binary", body)
+		}
+	}
+}
+
+func TestWapPushSIRequest(t *testing.T) {
+	request, err := twizo.NewWapPushSIRequest(
+		[]twizo.Recipient{"31600000000"},
+		"http://www.twizo.com",
+		"Twizo",
+		"Sender",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	j, err := json.Marshal(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var data struct {
+		Body string `json:"body"`
+		Dcs  int    `json:"dcs"`
+		Udh  string `json:"udh"`
+	}
+	if err := json.Unmarshal(j, &data); err != nil {
+		t.Fatal(err)
+	}
+
+	body := "010601AE" + "02056A00" + "45C6" +
+		"0D03" + hex.EncodeToString([]byte("twizo.com")) + "00" + "0701" +
+		"03" + hex.EncodeToString([]byte("Twizo")) + "00" + "0101"
+	if !strings.EqualFold(data.Body, body) {
+		t.Fatalf("Invalid body expecting [%s] got [%s]", strings.ToUpper(body), strings.ToUpper(data.Body))
+	}
+	if data.Dcs != 4 {
+		t.Fatalf("Invalid dcs expecting [4] got [%d]", data.Dcs)
+	}
+	if data.Udh != "0605040B8423F0" {
+		t.Fatalf("Invalid udh expecting [0605040B8423F0] got [%s]", data.Udh)
+	}
+}
+
+func TestWapPushSLRequest(t *testing.T) {
+	request, err := twizo.NewWapPushSLRequest([]twizo.Recipient{"31600000000"}, "https://twizo.com/app", "Sender")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, _ := request.GetBodyAsByteArr()
+	expect := "010601B0" + "02066A00" + "85" + "0B03" + hex.EncodeToString([]byte("twizo.com/app")) + "00" + "0501"
+	if strings.ToUpper(hex.EncodeToString(body)) != strings.ToUpper(expect) {
+		t.Fatalf("Invalid body expecting [%s] got [%X]", strings.ToUpper(expect), body)
+	}
+}
+
+func TestVCardSmsRequest(t *testing.T) {
+	vCard := "BEGIN:VCARD\r\nVERSION:2.1\r\nN:Demo;Twizo\r\nTEL:+31600000000\r\nEND:VCARD\r\n"
+	request, err := twizo.NewVCardSmsRequest([]twizo.Recipient{"31600000000"}, vCard, "Sender")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *request.GetUdh() != "06050423F40000" {
+		t.Fatalf("Invalid udh expecting [06050423F40000] got [%s]", *request.GetUdh())
+	}
+
+	if _, err := twizo.NewVCalendarSmsRequest([]twizo.Recipient{"31600000000"}, vCard, "Sender"); err == nil {
+		t.Fatal("Expecting error for vCard as vCalendar got [nil]")
+	}
+}
+
+func TestPortSmsRequestParts(t *testing.T) {
+	body := make([]byte, 200)
+	request, err := twizo.NewPortSmsRequest([]twizo.Recipient{"31600000000"}, body, "Sender", 5000, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parts, err := request.Parts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parts) != 2 {
+		t.Fatalf("Invalid amount of parts expecting [2] got [%d]", len(parts))
+	}
+	for i, part := range parts {
+		udh := *part.GetUdh()
+		if !strings.HasPrefix(udh, "0B0504138800000003") || !strings.HasSuffix(udh, []string{"0201", "0202"}[i]) {
+			t.Fatalf("Invalid udh for part [%d] got [%s]", i+1, udh)
+		}
+		if !part.IsBinary() {
+			t.Fatalf("Part [%d] should be binary", i+1)
+		}
+	}
+}
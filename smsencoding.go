@@ -0,0 +1,291 @@
+package twizo
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+// SmsEncoding is the alphabet used to send a message
+type SmsEncoding int
+
+const (
+	// SmsEncodingGSM7 the GSM 03.38 7 bit default alphabet, including the extension table
+	SmsEncodingGSM7 SmsEncoding = 0
+
+	// SmsEncodingUCS2 UCS-2, used when the body contains characters not in the GSM 03.38 alphabet
+	SmsEncodingUCS2 SmsEncoding = 1
+
+	// SmsEncodingBinary 8 bit data, used when the dcs is binary
+	SmsEncodingBinary SmsEncoding = 2
+)
+
+// String returns the name of the encoding
+func (encoding SmsEncoding) String() string {
+	switch encoding {
+	case SmsEncodingGSM7:
+		return "GSM-7"
+	case SmsEncodingUCS2:
+		return "UCS-2"
+	case SmsEncodingBinary:
+		return "binary"
+	}
+	return fmt.Sprintf("SmsEncoding(%d)", int(encoding))
+}
+
+// smsUserDataSize is the amount of bytes available for the udh and body of one message
+const smsUserDataSize = 140
+
+// gsm7Basic is the GSM 03.38 default alphabet, the escape to the extension table (0x1B) is a space here
+const gsm7Basic = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞ ÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?" +
+	"¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+
+// gsm7Extension are the characters of the GSM 03.38 extension table, they take two septets
+const gsm7Extension = "\f^{}\\[~]|€"
+
+// gsm7Septets returns the amount of septets needed for r, 0 when r is not in the GSM 03.38 alphabet
+func gsm7Septets(r rune) int {
+	if strings.ContainsRune(gsm7Basic, r) {
+		return 1
+	}
+	if strings.ContainsRune(gsm7Extension, r) {
+		return 2
+	}
+	return 0
+}
+
+// DetectSmsEncoding returns SmsEncodingGSM7 when body fits the GSM 03.38 alphabet, otherwise SmsEncodingUCS2
+func DetectSmsEncoding(body string) SmsEncoding {
+	for _, r := range body {
+		if gsm7Septets(r) == 0 {
+			return SmsEncodingUCS2
+		}
+	}
+	return SmsEncodingGSM7
+}
+
+// SegmentInfo describes how a message is split into segments (parts)
+type SegmentInfo struct {
+	// Encoding is the alphabet used to send the message
+	Encoding SmsEncoding
+
+	// Length is the length of the body in septets for GSM-7, in 16 bit characters for UCS-2
+	// and in bytes for binary messages
+	Length int
+
+	// Segments is the amount of messages needed to send the body to one recipient
+	Segments int
+
+	// PerSegment is the maximum length of the body in every segment
+	PerSegment int
+
+	// Remaining is the length still available in the last segment
+	Remaining int
+}
+
+// segmentCapacity returns the maximum length of the body in a message with a udh of udhSize bytes
+func segmentCapacity(encoding SmsEncoding, udhSize int) int {
+	free := smsUserDataSize - udhSize
+	switch encoding {
+	case SmsEncodingGSM7:
+		return free * 8 / 7
+	case SmsEncodingUCS2:
+		return free / 2
+	}
+	return free
+}
+
+// smsUnits splits body into the smallest pieces that can not be split over two messages and
+// returns them with their length
+func smsUnits(encoding SmsEncoding, body []byte) ([][]byte, []int) {
+	var units [][]byte
+	var lengths []int
+
+	if encoding == SmsEncodingBinary {
+		for i := range body {
+			units = append(units, body[i:i+1])
+			lengths = append(lengths, 1)
+		}
+		return units, lengths
+	}
+
+	for _, r := range string(body) {
+		length := 1
+		if encoding == SmsEncodingGSM7 {
+			length = gsm7Septets(r)
+		} else if r > 0xFFFF {
+			// outside the basic multilingual plane, encoded as a surrogate pair
+			length = 2
+		}
+		units = append(units, []byte(string(r)))
+		lengths = append(lengths, length)
+	}
+	return units, lengths
+}
+
+// smsSplit splits body into segments of at most capacity, the length of the last segment is returned
+func smsSplit(units [][]byte, lengths []int, capacity int) ([][]byte, int) {
+	segments := [][]byte{{}}
+	length := 0
+	for i, unit := range units {
+		if length+lengths[i] > capacity {
+			segments = append(segments, []byte{})
+			length = 0
+		}
+		segments[len(segments)-1] = append(segments[len(segments)-1], unit...)
+		length += lengths[i]
+	}
+	return segments, length
+}
+
+// encoding returns the encoding of the request, based on the dcs or the body
+func (request SmsRequest) encoding() SmsEncoding {
+	if request.IsBinary() {
+		return SmsEncodingBinary
+	}
+	if request.dcs&0xC0 == 0 && request.dcs&0x0C == 0x08 {
+		return SmsEncodingUCS2
+	}
+	return DetectSmsEncoding(string(request.body))
+}
+
+// dcsFor returns the dcs of the request with the alphabet of encoding
+func (request SmsRequest) dcsFor(encoding SmsEncoding) int {
+	if request.dcs&0xC0 != 0 || encoding == SmsEncodingBinary {
+		// not the general data coding group, leave it as is
+		return request.dcs
+	}
+	if encoding == SmsEncodingUCS2 {
+		return request.dcs&^0x0C | 0x08
+	}
+	return request.dcs &^ 0x0C
+}
+
+// udhElements returns the information elements of the udh set on the request, without the length
+func (request SmsRequest) udhElements() ([]byte, error) {
+	if request.udh == nil || *request.udh == "" {
+		return nil, nil
+	}
+
+	udh, err := hex.DecodeString(*request.udh)
+	if err != nil {
+		return nil, fmt.Errorf("invalid udh [%s]: %v", *request.udh, err)
+	}
+	if int(udh[0]) != len(udh)-1 {
+		return nil, fmt.Errorf("invalid udh [%s]: length [%d] does not match [%d]", *request.udh, udh[0], len(udh)-1)
+	}
+
+	return udh[1:], nil
+}
+
+// udhSize returns the size of a udh containing elements
+func udhSize(elements []byte) int {
+	if len(elements) == 0 {
+		return 0
+	}
+	return len(elements) + 1
+}
+
+// concatElementSize is the size of the concatenated sms information element with an 8 bit reference
+const concatElementSize = 5
+
+func (request SmsRequest) segments() ([][]byte, SegmentInfo, error) {
+	elements, err := request.udhElements()
+	if err != nil {
+		return nil, SegmentInfo{}, err
+	}
+
+	info := SegmentInfo{Encoding: request.encoding()}
+	units, lengths := smsUnits(info.Encoding, request.body)
+	for _, length := range lengths {
+		info.Length += length
+	}
+
+	info.PerSegment = segmentCapacity(info.Encoding, udhSize(elements))
+	if info.Length > info.PerSegment {
+		// every part needs a udh with the concatenated sms information element
+		info.PerSegment = segmentCapacity(info.Encoding, 1+len(elements)+concatElementSize)
+	}
+
+	segments, last := smsSplit(units, lengths, info.PerSegment)
+	info.Segments = len(segments)
+	info.Remaining = info.PerSegment - last
+
+	return segments, info, nil
+}
+
+// SegmentInfo returns the encoding, length and amount of segments needed to send the body, taking
+// the dcs and udh set on the request into account. Every recipient costs this amount of messages.
+func (request SmsRequest) SegmentInfo() (SegmentInfo, error) {
+	_, info, err := request.segments()
+	return info, err
+}
+
+// Parts splits the request into advanced requests, one per segment, with the dcs and the
+// concatenated sms udh set. The udh set on the request is kept in every part.
+func (request SmsRequest) Parts() ([]*SmsRequest, error) {
+	segments, info, err := request.segments()
+	if err != nil {
+		return nil, err
+	}
+	if info.Segments > 255 {
+		return nil, fmt.Errorf("body needs [%d] segments, at most 255 are allowed", info.Segments)
+	}
+
+	elements, _ := request.udhElements()
+	reference := byte(rand.Intn(256))
+
+	parts := make([]*SmsRequest, 0, len(segments))
+	for i, segment := range segments {
+		part := request
+		part.submitType = SmsSubmitTypeAdvanced
+		part.dcs = request.dcsFor(info.Encoding)
+		part.body = segment
+
+		partElements := elements
+		if len(segments) > 1 {
+			partElements = append(
+				append([]byte{}, elements...),
+				0x00, 0x03, reference, byte(len(segments)), byte(i+1),
+			)
+		}
+		if len(partElements) > 0 {
+			udh := strings.ToUpper(hex.EncodeToString(append([]byte{byte(len(partElements))}, partElements...)))
+			part.udh = &udh
+		}
+
+		parts = append(parts, &part)
+	}
+
+	return parts, nil
+}
+
+// SubmitParts submits the parts of the request, see Parts
+func (request *SmsRequest) SubmitParts() (*SmsResponses, error) {
+	return request.SubmitPartsContext(context.Background())
+}
+
+// SubmitPartsContext submits the parts of the request using ctx for the calls. When a part fails
+// the responses of the parts submitted before are returned with the error.
+func (request *SmsRequest) SubmitPartsContext(ctx context.Context) (*SmsResponses, error) {
+	parts, err := request.Parts()
+	if err != nil {
+		return nil, err
+	}
+
+	items := []SmsResponse{}
+	responses := &SmsResponses{Responses: &items}
+	for _, part := range parts {
+		partResponses, err := part.SubmitContext(ctx)
+		if err != nil {
+			return responses, err
+		}
+		if partResponses.Responses != nil {
+			items = append(items, *partResponses.Responses...)
+		}
+	}
+
+	return responses, nil
+}
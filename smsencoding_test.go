@@ -0,0 +1,126 @@
+package twizo_test
+
+import (
+	"strings"
+	"testing"
+
+	twizo "github.com/twizoapi/lib-api-go"
+	. "github.com/twizoapi/lib-api-go/testing"
+)
+
+func init() {
+	twizo.APIKey = TestAPIKey
+	twizo.RegionCurrent = TestRegion
+}
+
+func TestDetectSmsEncoding(t *testing.T) {
+	tests := map[string]twizo.SmsEncoding{
+		"Hello @ world £5":   twizo.SmsEncodingGSM7,
+		"Price {10} €, [ok]": twizo.SmsEncodingGSM7,
+		"Héllo ÄÖÑÜ§¿":       twizo.SmsEncodingGSM7,
+		"Ça va? ç":           twizo.SmsEncodingUCS2,
+		"Привет":             twizo.SmsEncodingUCS2,
+		"Smile 😀":            twizo.SmsEncodingUCS2,
+	}
+
+	for body, expect := range tests {
+		if encoding := twizo.DetectSmsEncoding(body); encoding != expect {
+			t.Errorf("Invalid encoding for [%s] expecting [%s] got [%s]", body, expect, encoding)
+		}
+	}
+}
+
+func TestSmsRequestSegmentInfo(t *testing.T) {
+	tests := []struct {
+		body   string
+		expect twizo.SegmentInfo
+	}{
+		{strings.Repeat("a", 160), twizo.SegmentInfo{twizo.SmsEncodingGSM7, 160, 1, 160, 0}},
+		{strings.Repeat("a", 161), twizo.SegmentInfo{twizo.SmsEncodingGSM7, 161, 2, 153, 145}},
+		{strings.Repeat("€", 80), twizo.SegmentInfo{twizo.SmsEncodingGSM7, 160, 1, 160, 0}},
+		// the escape and the extension character are not split over two segments
+		{
+			strings.Repeat("a", 152) + "€" + strings.Repeat("a", 9),
+			twizo.SegmentInfo{twizo.SmsEncodingGSM7, 163, 2, 153, 142},
+		},
+		{strings.Repeat("ç", 70), twizo.SegmentInfo{twizo.SmsEncodingUCS2, 70, 1, 70, 0}},
+		{strings.Repeat("ç", 71), twizo.SegmentInfo{twizo.SmsEncodingUCS2, 71, 2, 67, 63}},
+		{strings.Repeat("😀", 35), twizo.SegmentInfo{twizo.SmsEncodingUCS2, 70, 1, 70, 0}},
+	}
+
+	for _, test := range tests {
+		request, err := twizo.NewSmsRequest([]twizo.Recipient{"31600000000"}, test.body, "Sender")
+		if err != nil {
+			t.Fatal(err)
+		}
+		info, err := request.SegmentInfo()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info != test.expect {
+			t.Errorf("Invalid segment info for [%s] expecting [%+v] got [%+v]", test.body, test.expect, info)
+		}
+	}
+}
+
+func TestSmsRequestParts(t *testing.T) {
+	body := strings.Repeat("ç", 100)
+	request, err := twizo.NewSmsRequest([]twizo.Recipient{"31600000000"}, body, "Sender")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parts, err := request.Parts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parts) != 2 {
+		t.Fatalf("Invalid amount of parts expecting [2] got [%d]", len(parts))
+	}
+
+	var joined string
+	var reference string
+	for i, part := range parts {
+		if part.GetDcs() != 8 {
+			t.Fatalf("Invalid dcs expecting [8] got [%d]", part.GetDcs())
+		}
+		udh := *part.GetUdh()
+		if len(udh) != 12 || udh[:6] != "050003" || udh[8:] != []string{"0201", "0202"}[i] {
+			t.Fatalf("Invalid udh for part [%d] got [%s]", i+1, udh)
+		}
+		if reference == "" {
+			reference = udh[6:8]
+		} else if udh[6:8] != reference {
+			t.Fatalf("Invalid udh reference expecting [%s] got [%s]", reference, udh[6:8])
+		}
+		partBody, _ := part.GetBodyAsString()
+		joined += partBody
+	}
+	if joined != body {
+		t.Fatalf("Invalid joined body expecting [%s] got [%s]", body, joined)
+	}
+
+	// a udh already set on the request is kept in every part
+	udh := "060504158A0000"
+	request.SetUdh(&udh)
+	info, err := request.SegmentInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.PerSegment != 64 {
+		t.Fatalf("Invalid per segment expecting [64] got [%d]", info.PerSegment)
+	}
+	parts, err = request.Parts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(*parts[0].GetUdh(), "0B0504158A00000003") {
+		t.Fatalf("Invalid udh expecting prefix [0B0504158A00000003] got [%s]", *parts[0].GetUdh())
+	}
+
+	udh = "0605"
+	request.SetUdh(&udh)
+	if _, err := request.Parts(); err == nil {
+		t.Fatal("Expecting error for invalid udh got [nil]")
+	}
+}
@@ -0,0 +1,184 @@
+package twizo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"text/template"
+)
+
+// SmsTemplateError is the error of rendering or submitting the body of one or more recipients
+type SmsTemplateError struct {
+	// Recipients are the recipients of the body, none of them was sent the message
+	Recipients []Recipient
+
+	Err error
+}
+
+// Error returns the error message
+func (e *SmsTemplateError) Error() string {
+	return fmt.Sprintf("sms template for recipients %v failed: %v", e.Recipients, e.Err)
+}
+
+// Unwrap returns the error of rendering or submitting the body
+func (e *SmsTemplateError) Unwrap() error {
+	return e.Err
+}
+
+// SmsTemplate renders a text/template body per recipient, recipients with the same rendered body share
+// one submit
+type SmsTemplate struct {
+	// MaxSegments is the maximum amount of segments of a rendered body, there is no limit when 0
+	MaxSegments int
+
+	// GSM7Only rejects rendered bodies that can not be sent using the GSM-7 alphabet
+	GSM7Only bool
+
+	template *template.Template
+	request  *SmsRequest
+}
+
+// NewSmsTemplate creates a new sms template, body is parsed as text/template and executed with the data
+// of every recipient. Using a key missing from the data is an error.
+func NewSmsTemplate(body string, sender string) (*SmsTemplate, error) {
+	return DefaultClient.NewSmsTemplate(body, sender)
+}
+
+// NewSmsTemplate creates a new sms template that will be submitted using the client
+func (c *Client) NewSmsTemplate(body string, sender string) (*SmsTemplate, error) {
+	tmpl, err := template.New("sms").Option("missingkey=error").Parse(body)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := c.NewSmsRequest(nil, "", sender)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SmsTemplate{template: tmpl, request: request}, nil
+}
+
+// GetRequest returns the request every rendered body is submitted with, use it to set the tag,
+// callback url, validity, etc. The body and recipients of the request are ignored.
+func (t *SmsTemplate) GetRequest() *SmsRequest {
+	return t.request
+}
+
+// Render renders and validates the body of every recipient in data and returns a request per distinct
+// body, ordered by the first recipient of the request. The recipients are normalised using ParseRecipient,
+// nothing is returned when a recipient or body fails.
+func (t *SmsTemplate) Render(data map[Recipient]interface{}) ([]*SmsRequest, error) {
+	recipients := make([]Recipient, 0, len(data))
+	for recipient := range data {
+		recipients = append(recipients, recipient)
+	}
+	sort.Slice(recipients, func(i, j int) bool { return recipients[i] < recipients[j] })
+
+	country := clientOrDefault(t.request.client).getDefaultCountry()
+	parsed := map[Recipient]Recipient{}
+
+	var requests []*SmsRequest
+	bodies := map[string]*SmsRequest{}
+	for _, input := range recipients {
+		recipient, err := ParseRecipient(string(input), country)
+		if err != nil {
+			return nil, &SmsTemplateError{Recipients: []Recipient{input}, Err: err}
+		}
+		if other, ok := parsed[recipient]; ok {
+			err := fmt.Errorf("recipient is the same number as [%s]", other)
+			return nil, &SmsTemplateError{Recipients: []Recipient{input}, Err: err}
+		}
+		parsed[recipient] = input
+
+		body, err := t.render(data[input])
+		if err != nil {
+			return nil, &SmsTemplateError{Recipients: []Recipient{input}, Err: err}
+		}
+
+		if request, ok := bodies[body]; ok {
+			request.recipients = append(request.recipients, recipient)
+			continue
+		}
+
+		request := *t.request
+		request.recipients = []Recipient{recipient}
+		request.body = []byte(body)
+		requests = append(requests, &request)
+		bodies[body] = &request
+	}
+
+	return requests, nil
+}
+
+// render executes the template with data and validates the result
+func (t *SmsTemplate) render(data interface{}) (string, error) {
+	var buffer bytes.Buffer
+	if err := t.template.Execute(&buffer, data); err != nil {
+		return "", err
+	}
+	body := buffer.String()
+	if body == "" {
+		return "", errors.New("rendered body is empty")
+	}
+
+	request := *t.request
+	request.body = []byte(body)
+	info, err := request.SegmentInfo()
+	if err != nil {
+		return "", err
+	}
+	if t.GSM7Only && info.Encoding != SmsEncodingGSM7 {
+		return "", fmt.Errorf("rendered body [%s] requires [%s] encoding", body, info.Encoding)
+	}
+	if t.MaxSegments > 0 && info.Segments > t.MaxSegments {
+		return "", fmt.Errorf(
+			"rendered body [%s] needs [%d] segments, the maximum is [%d]",
+			body,
+			info.Segments,
+			t.MaxSegments,
+		)
+	}
+
+	return body, nil
+}
+
+// Send renders the body of every recipient in data and submits them
+func (t *SmsTemplate) Send(data map[Recipient]interface{}) (map[Recipient]*SmsResponse, error) {
+	return t.SendContext(context.Background(), data)
+}
+
+// SendContext renders the body of every recipient in data and submits them, a submit is made per distinct
+// body. The response of every (normalised) recipient is returned, when a submit fails a *SmsTemplateError
+// is returned together with the responses of the submits made before.
+func (t *SmsTemplate) SendContext(
+	ctx context.Context,
+	data map[Recipient]interface{},
+) (map[Recipient]*SmsResponse, error) {
+	requests, err := t.Render(data)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := map[Recipient]*SmsResponse{}
+	for _, request := range requests {
+		submitted, err := request.SubmitContext(ctx)
+		if err != nil {
+			return responses, &SmsTemplateError{Recipients: request.recipients, Err: err}
+		}
+		if submitted.Responses == nil {
+			continue
+		}
+		for i := range *submitted.Responses {
+			response := &(*submitted.Responses)[i]
+			// a body sent as multiple messages has a response per part, the first one is returned
+			if _, ok := responses[response.GetRecipient()]; !ok {
+				responses[response.GetRecipient()] = response
+			}
+		}
+	}
+
+	return responses, nil
+}
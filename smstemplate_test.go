@@ -0,0 +1,122 @@
+package twizo_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	twizo "github.com/twizoapi/lib-api-go"
+	. "github.com/twizoapi/lib-api-go/testing"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+func init() {
+	twizo.APIKey = TestAPIKey
+	twizo.RegionCurrent = TestRegion
+}
+
+func TestSmsTemplateSend(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var submits []string
+	httpmock.RegisterResponder(
+		http.MethodPost,
+		fmt.Sprintf("https://%s/%s/sms/submitsimple", twizo.GetHostForRegion(TestRegion), twizo.ClientAPIVersion),
+		func(req *http.Request) (*http.Response, error) {
+			body, _ := ioutil.ReadAll(req.Body)
+			var data struct {
+				Body       string   `json:"body"`
+				Recipients []string `json:"recipients"`
+				Tag        string   `json:"tag"`
+			}
+			if err := json.Unmarshal(body, &data); err != nil {
+				return httpmock.NewStringResponse(http.StatusBadRequest, ""), nil
+			}
+			submits = append(submits, fmt.Sprintf("%s %v %s", data.Body, data.Recipients, data.Tag))
+
+			var items []string
+			for _, recipient := range data.Recipients {
+				items = append(items, fmt.Sprintf(`{"messageId":"id-%s","recipient":"%s"}`, recipient, recipient))
+			}
+			return httpmock.NewStringResponse(
+				http.StatusCreated,
+				fmt.Sprintf(`{"_embedded":{"items":[%s]}}`, strings.Join(items, ",")),
+			), nil
+		},
+	)
+
+	template, err := twizo.NewSmsTemplate("Hello {{.Name}}, you owe {{.Amount}}", "Sender")
+	if err != nil {
+		t.Fatal(err)
+	}
+	template.GetRequest().SetTag("reminder")
+
+	type owes struct {
+		Name   string
+		Amount string
+	}
+	responses, err := template.Send(map[twizo.Recipient]interface{}{
+		"31600000001":    owes{"Ann", "€5"},
+		"+31 6 00000002": owes{"Bob", "€7"},
+		"0031-600000003": owes{"Ann", "€5"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the bodies are grouped in order of the recipients as given
+	expect := "[Hello Bob, you owe €7 [31600000002] reminder " +
+		"Hello Ann, you owe €5 [31600000003 31600000001] reminder]"
+	if fmt.Sprint(submits) != expect {
+		t.Fatalf("Invalid submits expecting [%s] got [%v]", expect, submits)
+	}
+	for _, recipient := range []twizo.Recipient{"31600000001", "31600000002", "31600000003"} {
+		response, ok := responses[recipient]
+		if !ok || response.GetMessageID() != "id-"+string(recipient) {
+			t.Fatalf("Invalid response for recipient [%s] got [%v]", recipient, response)
+		}
+	}
+}
+
+func TestSmsTemplateRenderErrors(t *testing.T) {
+	template, err := twizo.NewSmsTemplate("Hello {{.Name}}", "Sender")
+	if err != nil {
+		t.Fatal(err)
+	}
+	template.GSM7Only = true
+	template.MaxSegments = 1
+
+	tests := map[string]interface{}{
+		"missing key": map[string]string{"Other": "Ann"},
+		"encoding":    map[string]string{"Name": "Олег"},
+		"segments":    map[string]string{"Name": strings.Repeat("a", 160)},
+	}
+	_, err = template.Render(map[twizo.Recipient]interface{}{"999123456": nil})
+	if !errors.Is(err, twizo.ErrInvalidRecipient) {
+		t.Fatalf("Expecting error matching [%v] got [%v]", twizo.ErrInvalidRecipient, err)
+	}
+	for name, data := range tests {
+		_, err := template.Render(map[twizo.Recipient]interface{}{"31600000001": data})
+		var templateError *twizo.SmsTemplateError
+		if !errors.As(err, &templateError) {
+			t.Fatalf("Expecting [%s] to return a *SmsTemplateError got [%v]", name, err)
+		}
+		if fmt.Sprint(templateError.Recipients) != "[31600000001]" {
+			t.Fatalf(
+				"Invalid recipients for [%s] expecting [[31600000001]] got [%v]",
+				name,
+				templateError.Recipients,
+			)
+		}
+	}
+
+	if _, err := twizo.NewSmsTemplate("Hello {{.Name", "Sender"); err == nil {
+		t.Fatal("Expecting error for invalid template got [nil]")
+	}
+}
@@ -0,0 +1,122 @@
+package twizo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultStatusConcurrency is the default maximum amount of concurrent calls when requesting the
+// status of multiple responses
+const DefaultStatusConcurrency = 8
+
+// StatusError is returned when requesting the status of multiple responses failed for some of them,
+// the responses that did not fail are updated
+type StatusError struct {
+	// Failures contains the failed responses in order of their index
+	Failures []StatusFailure
+}
+
+// StatusFailure is the failed status request of one response
+type StatusFailure struct {
+	// Index is the index of the response
+	Index int
+
+	// MessageID is the message id of the response, it might be empty or shared by multiple responses
+	MessageID string
+
+	Err error
+}
+
+// Error returns the error message
+func (e *StatusError) Error() string {
+	if len(e.Failures) == 1 {
+		return fmt.Sprintf("status of message [%s] failed: %v", e.Failures[0].MessageID, e.Failures[0].Err)
+	}
+
+	messages := make([]string, 0, len(e.Failures))
+	for _, failure := range e.Failures {
+		messages = append(messages, fmt.Sprintf("[%d:%s] %v", failure.Index, failure.MessageID, failure.Err))
+	}
+	return fmt.Sprintf("status of %d messages failed: %s", len(e.Failures), strings.Join(messages, ", "))
+}
+
+// MessageIDs returns the ids of the failed messages in order of their index
+func (e *StatusError) MessageIDs() []string {
+	messageIDs := make([]string, 0, len(e.Failures))
+	for _, failure := range e.Failures {
+		messageIDs = append(messageIDs, failure.MessageID)
+	}
+	return messageIDs
+}
+
+// Is returns true when one of the errors matches target
+func (e *StatusError) Is(target error) bool {
+	for _, failure := range e.Failures {
+		if errors.Is(failure.Err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first error, in order of the index, that matches target
+func (e *StatusError) As(target interface{}) bool {
+	for _, failure := range e.Failures {
+		if errors.As(failure.Err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// statusAll calls status for 0 to n with at most concurrency calls at a time, status returns the
+// message id and the error of the call
+func statusAll(
+	ctx context.Context,
+	n int,
+	concurrency int,
+	status func(ctx context.Context, i int) (string, error),
+) error {
+	if concurrency <= 0 {
+		concurrency = DefaultStatusConcurrency
+	}
+	if concurrency > n {
+		concurrency = n
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var failed []StatusFailure
+
+	next := make(chan int)
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				messageID, err := status(ctx, i)
+				if err != nil {
+					mu.Lock()
+					failed = append(failed, StatusFailure{Index: i, MessageID: messageID, Err: err})
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	if len(failed) > 0 {
+		sort.Slice(failed, func(a, b int) bool { return failed[a].Index < failed[b].Index })
+		return &StatusError{Failures: failed}
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package twizo_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	twizo "github.com/twizoapi/lib-api-go"
+	. "github.com/twizoapi/lib-api-go/testing"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+func init() {
+	twizo.APIKey = TestAPIKey
+	twizo.RegionCurrent = TestRegion
+}
+
+func TestSmsResponsesStatusPartial(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	responses := newTrackedResponses(t)
+	responses.Concurrency = 2
+
+	HTTPMockSend(
+		http.MethodGet,
+		smsStatusURL("one-1"),
+		http.StatusOK,
+		smsStatusJSON("one-1", "31600000001", twizo.SmsStatusCodeDelivered),
+		nil,
+	)
+	HTTPMockSend(
+		http.MethodGet,
+		smsStatusURL("one-2"),
+		http.StatusOK,
+		smsStatusJSON("one-2", "31600000001", twizo.SmsStatusCodeExpired),
+		nil,
+	)
+	httpmock.RegisterResponder(http.MethodGet, smsStatusURL("two"), newProblemResponder(http.StatusNotFound, 0))
+
+	err := responses.Status()
+
+	var statusError *twizo.StatusError
+	if !errors.As(err, &statusError) {
+		t.Fatalf("Invalid error expecting [*twizo.StatusError] got [%#v]", err)
+	}
+	if fmt.Sprint(statusError.MessageIDs()) != "[two]" {
+		t.Fatalf("Invalid failed message ids expecting [[two]] got [%v]", statusError.MessageIDs())
+	}
+	if !errors.Is(err, twizo.ErrNotFound) {
+		t.Fatalf("Expecting error to match [%v] got [%v]", twizo.ErrNotFound, err)
+	}
+
+	expect := []twizo.SmsStatusCode{
+		twizo.SmsStatusCodeDelivered,
+		twizo.SmsStatusCodeExpired,
+		twizo.SmsStatusCodeNoStatus,
+	}
+	for i, item := range responses.GetItems() {
+		if item.GetStatusCode() != expect[i] {
+			t.Fatalf("Invalid status of [%s] expecting [%d] got [%d]", item.GetMessageID(), expect[i], item.GetStatusCode())
+		}
+	}
+}
+
+func TestSmsResponsesStatusDuplicateMessageIDs(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	responses := &twizo.SmsResponses{}
+	err := json.Unmarshal(
+		[]byte(fmt.Sprintf(
+			`{"_embedded":{"items":[%s,%s,%s]}}`,
+			smsStatusJSON("dup", "31600000001", twizo.SmsStatusCodeNoStatus),
+			smsStatusJSON("one", "31600000002", twizo.SmsStatusCodeNoStatus),
+			smsStatusJSON("dup", "31600000003", twizo.SmsStatusCodeNoStatus),
+		)),
+		responses,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	HTTPMockSend(
+		http.MethodGet,
+		smsStatusURL("one"),
+		http.StatusOK,
+		smsStatusJSON("one", "31600000002", twizo.SmsStatusCodeDelivered),
+		nil,
+	)
+	httpmock.RegisterResponder(http.MethodGet, smsStatusURL("dup"), newProblemResponder(http.StatusNotFound, 0))
+
+	var statusError *twizo.StatusError
+	if err := responses.Status(); !errors.As(err, &statusError) {
+		t.Fatalf("Invalid error expecting [*twizo.StatusError] got [%#v]", err)
+	}
+	// both failures are reported, in order of their index
+	if len(statusError.Failures) != 2 || statusError.Failures[0].Index != 0 || statusError.Failures[1].Index != 2 {
+		t.Fatalf("Invalid failures expecting indexes [0 2] got [%v]", statusError.Failures)
+	}
+	if fmt.Sprint(statusError.MessageIDs()) != "[dup dup]" {
+		t.Fatalf("Invalid failed message ids expecting [[dup dup]] got [%v]", statusError.MessageIDs())
+	}
+}
+
+func TestNumberLookupResponsesStatus(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var items []string
+	for _, messageID := range []string{"one", "two"} {
+		statusURL := fmt.Sprintf(
+			"https://%s/%s/numberlookup/submit/%s",
+			twizo.GetHostForRegion(TestRegion),
+			twizo.ClientAPIVersion,
+			messageID,
+		)
+		items = append(items, fmt.Sprintf(
+			`{"messageId":"%s","number":"%s","statusCode":0,"_links":{"self":{"href":"%s"}}}`,
+			messageID,
+			messageID,
+			statusURL,
+		))
+		HTTPMockSend(
+			http.MethodGet,
+			statusURL,
+			http.StatusOK,
+			fmt.Sprintf(`{"messageId":"%s","number":"%s","statusCode":1,"operator":"operator"}`, messageID, messageID),
+			nil,
+		)
+	}
+
+	responses := &twizo.NumberLookupResponses{}
+	err := json.Unmarshal([]byte(fmt.Sprintf(`{"_embedded":{"items":[%s,%s]}}`, items[0], items[1])), responses)
+	if err != nil {
+		t.Fatal(err)
+	}
+	responses.Concurrency = 1
+
+	if err := responses.Status(); err != nil {
+		t.Fatal(err)
+	}
+	for _, item := range responses.GetItems() {
+		if item.GetStatusCode() != twizo.NumberLookupStatusCodeDelivered {
+			t.Fatalf("Invalid status of [%s] expecting [1] got [%d]", item.GetMessageID(), item.GetStatusCode())
+		}
+		if item.GetOperator() == nil || *item.GetOperator() != "operator" {
+			t.Fatalf("Invalid operator of [%s] expecting [operator] got [%v]", item.GetMessageID(), item.GetOperator())
+		}
+	}
+}
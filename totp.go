@@ -1,6 +1,7 @@
 package twizo
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -21,6 +22,7 @@ func (request *jsonTotpRequest) copyFrom(r *TotpRequest) {
 type TotpRequest struct {
 	identifier string
 	issuer     string
+	client     *Client
 }
 
 // MarshalJSON is used to convert TotpRequest to json
@@ -49,6 +51,11 @@ func (request *TotpRequest) SetIssuer(issuer string) {
 
 // Create a totpCreate code for an identifier
 func (request *TotpRequest) Create(issuer string) (*TotpResponse, error) {
+	return request.CreateContext(context.Background(), issuer)
+}
+
+// CreateContext creates a totp for an identifier using ctx for the call
+func (request *TotpRequest) CreateContext(ctx context.Context, issuer string) (*TotpResponse, error) {
 	request.SetIssuer(issuer)
 
 	response := &TotpResponse{}
@@ -58,7 +65,9 @@ func (request *TotpRequest) Create(issuer string) (*TotpResponse, error) {
 		return nil, err
 	}
 
-	err = GetClient(RegionCurrent, APIKey).Call(
+	err = clientOrDefault(request.client).call(
+		ctx,
+		EndpointTotpCreate,
 		http.MethodPost,
 		apiURL,
 		request,
@@ -69,19 +78,27 @@ func (request *TotpRequest) Create(issuer string) (*TotpResponse, error) {
 	if err != nil {
 		return nil, err
 	}
+	response.setClient(request.client)
 
 	return response, nil
 }
 
 // Check retrieves some information about the id (previousely created with Create)
 func (request TotpRequest) Check() (*TotpResponse, error) {
+	return request.CheckContext(context.Background())
+}
+
+// CheckContext retrieves information about the identifier using ctx for the call
+func (request TotpRequest) CheckContext(ctx context.Context) (*TotpResponse, error) {
 	response := &TotpResponse{}
 	apiURL, err := GetURLFor(fmt.Sprintf("totp/%s", url.PathEscape(request.GetIdentifier())))
 	if err != nil {
 		return nil, err
 	}
 
-	err = GetClient(RegionCurrent, APIKey).Call(
+	err = clientOrDefault(request.client).call(
+		ctx,
+		EndpointTotpCheck,
 		http.MethodGet,
 		apiURL,
 		nil,
@@ -92,12 +109,18 @@ func (request TotpRequest) Check() (*TotpResponse, error) {
 	if err != nil {
 		return nil, err
 	}
+	response.setClient(request.client)
 
 	return response, nil
 }
 
 // Verify token for an identifier
 func (request TotpRequest) Verify(token string) (*TotpResponse, error) {
+	return request.VerifyContext(context.Background(), token)
+}
+
+// VerifyContext verifies the token for an identifier using ctx for the call
+func (request TotpRequest) VerifyContext(ctx context.Context, token string) (*TotpResponse, error) {
 	response := &TotpResponse{}
 
 	apiURL, err := GetURLFor(fmt.Sprintf("totp/%s", url.PathEscape(request.GetIdentifier())))
@@ -109,7 +132,9 @@ func (request TotpRequest) Verify(token string) (*TotpResponse, error) {
 	q.Set("token", token)
 	apiURL.RawQuery = q.Encode()
 
-	err = GetClient(RegionCurrent, APIKey).Call(
+	err = clientOrDefault(request.client).call(
+		ctx,
+		EndpointTotpVerify,
 		http.MethodGet,
 		apiURL,
 		nil,
@@ -120,18 +145,26 @@ func (request TotpRequest) Verify(token string) (*TotpResponse, error) {
 	if err != nil {
 		return nil, err
 	}
+	response.setClient(request.client)
 
 	return response, nil
 }
 
 // Delete the existing (if any) backup codes for identifier
 func (request TotpRequest) Delete() error {
+	return request.DeleteContext(context.Background())
+}
+
+// DeleteContext deletes the totp of the identifier using ctx for the call
+func (request TotpRequest) DeleteContext(ctx context.Context) error {
 	apiURL, err := GetURLFor(fmt.Sprintf("totp/%s", url.PathEscape(request.GetIdentifier())))
 	if err != nil {
 		return err
 	}
 
-	return GetClient(RegionCurrent, APIKey).Call(
+	return clientOrDefault(request.client).call(
+		ctx,
+		EndpointTotpDelete,
 		http.MethodDelete,
 		apiURL,
 		request,
@@ -160,6 +193,12 @@ type TotpResponse struct {
 	links                HATEOASLinks
 }
 
+func (response *TotpResponse) setClient(c *Client) {
+	if response.verificationResponse != nil {
+		response.verificationResponse.client = c
+	}
+}
+
 // UnmarshalJSON the json response to struct
 func (response *TotpResponse) UnmarshalJSON(j []byte) error {
 	var jsonResponse = &jsonTotpResponse{}
@@ -224,32 +263,58 @@ func (response TotpResponse) GetVerificationResponse() *VerificationResponse {
 
 // NewTotpRequest creates a new BackupCodeRequest
 func NewTotpRequest(id string) *TotpRequest {
+	return DefaultClient.NewTotpRequest(id)
+}
+
+// NewTotpRequest creates a new TotpRequest that will use the client
+func (c *Client) NewTotpRequest(id string) *TotpRequest {
 	request := &TotpRequest{
 		identifier: id,
+		client:     c,
 	}
 	return request
 }
 
 // TotpCreate creates new totpCreate for an identifier
 func TotpCreate(id string, issuer string) (*TotpResponse, error) {
-	request := NewTotpRequest(id)
+	return DefaultClient.TotpCreate(id, issuer)
+}
+
+// TotpCreate creates new totp for an identifier using the client
+func (c *Client) TotpCreate(id string, issuer string) (*TotpResponse, error) {
+	request := c.NewTotpRequest(id)
 	return request.Create(issuer)
 }
 
 // TotpCheck creates new totpCreate for an identifier
 func TotpCheck(id string) (*TotpResponse, error) {
-	request := NewTotpRequest(id)
+	return DefaultClient.TotpCheck(id)
+}
+
+// TotpCheck retrieves the totp of an identifier using the client
+func (c *Client) TotpCheck(id string) (*TotpResponse, error) {
+	request := c.NewTotpRequest(id)
 	return request.Check()
 }
 
 // TotpDelete will delete the totpCreate for the identifier supplied
 func TotpDelete(id string) error {
-	request := NewTotpRequest(id)
+	return DefaultClient.TotpDelete(id)
+}
+
+// TotpDelete will delete the totp for the identifier supplied using the client
+func (c *Client) TotpDelete(id string) error {
+	request := c.NewTotpRequest(id)
 	return request.Delete()
 }
 
 // TotpVerify will verify a token for an totpCreate
 func TotpVerify(id string, token string) (*TotpResponse, error) {
-	request := NewTotpRequest(id)
+	return DefaultClient.TotpVerify(id, token)
+}
+
+// TotpVerify will verify a token for the totp of an identifier using the client
+func (c *Client) TotpVerify(id string, token string) (*TotpResponse, error) {
+	request := c.NewTotpRequest(id)
 	return request.Verify(token)
 }
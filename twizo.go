@@ -19,11 +19,11 @@ package twizo
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
-	"log"
 	"net/http"
 	"net/url"
 	"reflect"
@@ -73,9 +73,6 @@ const (
 	ResultTypeCallbackPolling ResultType = 3
 )
 
-// DebugLogger contains the debug logger
-var DebugLogger = log.New(ioutil.Discard, "DEBUG: ", log.Ldate|log.Ltime|log.Lshortfile)
-
 var regionUrls = map[APIRegion]string{
 	APIRegionAsia:    "api-asia-01.twizo.com",
 	APIRegionEU:      "api-eu-01.twizo.com",
@@ -98,7 +95,15 @@ var APIKey string
 // RegionCurrent the current region : possible to override this setting
 var RegionCurrent = APIRegionDefault
 
+// DefaultCountry the ISO 3166-1 alpha-2 country of recipients in national format (starting with a
+// single 0), when empty they are rejected : possible to override this setting
+var DefaultCountry string
+
 // HTTPClientTimeout the current timeout on http calls
+//
+// Deprecated: this is only used for the default http client when the package
+// is loaded, use the Context variants of the calls to control deadlines and
+// cancellation per call.
 var HTTPClientTimeout = defaultHTTPTimeout
 
 // Recipient the recipient
@@ -113,31 +118,119 @@ type Response interface {
 	UnmarshalJSON(data []byte) error
 }
 
-// Client interface
-type Client interface {
-	Call(method string, path string, request Request, v interface{}) error
-}
-
-// HTTPClient is the actual http client
-type HTTPClient struct {
+// Client talks to the Twizo api using its own key, region and http client, this
+// allows a single process to use multiple applications or regions at the same
+// time. Fields left empty fall back to the package settings (APIKey,
+// RegionCurrent, DefaultCountry and SetHTTPClient), so a zero Client behaves
+// like the package level functions.
+type Client struct {
 	Region     APIRegion
 	Key        string
 	HTTPClient *http.Client
+
+	// BaseURL overrides the scheme and host (and optionally a path prefix) of
+	// all calls, when nil the host is derived from the region.
+	BaseURL *url.URL
+
+	// RetryPolicy describes how failed calls are retried, when nil calls are
+	// attempted only once.
+	RetryPolicy *RetryPolicy
+
+	// Middleware is called around every attempt of every call made by the
+	// client, the first middleware is the outermost.
+	Middleware []Middleware
+
+	// Logger receives every request and response, when nil DefaultLogger is used
+	Logger Logger
+
+	// LogSensitiveData logs tokens, backup codes, totp secrets and phone numbers
+	// as is, by default they are redacted
+	LogSensitiveData bool
+
+	// DefaultCountry is the ISO 3166-1 alpha-2 country of recipients in national format, when
+	// empty DefaultCountry of the package is used
+	DefaultCountry string
 }
 
-// NewRequest creates a new request, this allows it to be tested [todo: refactor]
-func (c HTTPClient) NewRequest(method string, url *url.URL, body io.Reader) (*http.Request, error) {
+// HTTPClient is the actual http client, kept for backwards compatibility
+type HTTPClient = Client
 
-	if url.Host == "" {
-		url.Host = GetHostForRegion(c.Region)
+// DefaultClient is used by all package level functions, it has no settings of its
+// own and therefore always follows APIKey, RegionCurrent, DefaultCountry and SetHTTPClient
+var DefaultClient = &Client{}
+
+// NewClient creates a new client for key in region
+func NewClient(key string, region APIRegion) *Client {
+	return &Client{
+		Region:     region,
+		Key:        key,
+		HTTPClient: GetHTTPClient(),
+	}
+}
+
+func clientOrDefault(c *Client) *Client {
+	if c == nil {
+		return DefaultClient
 	}
+	return c
+}
+
+func (c *Client) getKey() string {
+	if c.Key == "" {
+		return APIKey
+	}
+	return c.Key
+}
+
+func (c *Client) getRegion() APIRegion {
+	if c.Region == "" {
+		return RegionCurrent
+	}
+	return c.Region
+}
 
-	req, err := http.NewRequest(method, url.String(), body)
+func (c *Client) getDefaultCountry() string {
+	if c.DefaultCountry == "" {
+		return DefaultCountry
+	}
+	return c.DefaultCountry
+}
+
+func (c *Client) getRetryPolicy() RetryPolicy {
+	if c.RetryPolicy == nil {
+		return RetryPolicy{MaxAttempts: 1}
+	}
+	return *c.RetryPolicy
+}
+
+func (c *Client) getHTTPClient() *http.Client {
+	if c.HTTPClient == nil {
+		return GetHTTPClient()
+	}
+	return c.HTTPClient
+}
+
+// NewRequest creates a new request, this allows it to be tested [todo: refactor]
+func (c Client) NewRequest(method string, apiURL *url.URL, body io.Reader) (*http.Request, error) {
+	// work on a copy, apiURL is often a link owned by a response
+	u := *apiURL
+
+	if u.Host == "" {
+		if c.BaseURL != nil {
+			u.Scheme = c.BaseURL.Scheme
+			u.Host = c.BaseURL.Host
+			u.Path = strings.TrimSuffix(c.BaseURL.Path, "/") + u.Path
+		} else {
+			u.Host = GetHostForRegion(c.getRegion())
+		}
+	}
+
+	req, err := http.NewRequest(method, u.String(), body)
 	if err != nil {
 		return nil, err
 	}
 
-	req.SetBasicAuth(ClientAuthUser, c.Key)
+	req.SetBasicAuth(ClientAuthUser, c.getKey())
 
 	req.Header.Add("User-Agent", httpClientUserAgent)
 	req.Header.Add("Accept", "application/json")
@@ -146,62 +239,179 @@ func (c HTTPClient) NewRequest(method string, url *url.URL, body io.Reader) (*ht
 	return req, nil
 }
 
+// NewRequestContext creates a new request that is bound to ctx, cancelling ctx
+// or exceeding its deadline will abort the request
+func (c Client) NewRequestContext(
+	ctx context.Context,
+	method string,
+	apiURL *url.URL,
+	body io.Reader,
+) (*http.Request, error) {
+	req, err := c.NewRequest(method, apiURL, body)
+	if err != nil {
+		return nil, err
+	}
+
+	return req.WithContext(ctx), nil
+}
+
 // Call performs the actual call on the client
-func (c *HTTPClient) Call(method string, url *url.URL, request Request, expectCode int, v interface{}) error {
-	// convert request to body
-	requestBody := bytes.NewBuffer(nil)
+func (c *Client) Call(method string, url *url.URL, request Request, expectCode int, v interface{}) error {
+	return c.CallContext(context.Background(), method, url, request, expectCode, v)
+}
+
+// CallContext performs the actual call on the client, the context is passed on
+// to the transport so the call is aborted when ctx is cancelled or expires
+func (c *Client) CallContext(
+	ctx context.Context,
+	method string,
+	url *url.URL,
+	request Request,
+	expectCode int,
+	v interface{},
+) error {
+	return c.call(ctx, EndpointUnknown, method, url, request, expectCode, v)
+}
+
+// call performs the call to endpoint, passing every attempt through the middleware of the client
+func (c *Client) call(
+	ctx context.Context,
+	endpoint Endpoint,
+	method string,
+	url *url.URL,
+	request Request,
+	expectCode int,
+	v interface{},
+) error {
+	// no need to build and send the request if we are already done
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// convert request to body, it is kept around as it is needed for every attempt
+	var requestBody []byte
 	if request != nil {
 		body, err := json.Marshal(request)
 		if err != nil {
 			return err
 		}
 
-		requestBody.Write([]byte(body))
+		requestBody = body
 	}
 
-	// create new request
-	req, err := c.NewRequest(method, url, requestBody)
-	if err != nil {
-		return err
-	}
+	policy := c.getRetryPolicy()
+	handler := c.handler(c.do)
+	for attempt := 1; ; attempt++ {
+		// create new request
+		req, err := c.NewRequestContext(ctx, method, url, bytes.NewReader(requestBody))
+		if err != nil {
+			return err
+		}
 
-	if requestBody.Len() > 0 {
-		DebugLogger.Printf("Request %v [%v] body %s", req.Method, req.URL.String(), requestBody)
-	} else {
-		DebugLogger.Printf("Request %v [%v]", req.Method, req.URL.String())
-	}
+		// actually do the request and parse errors if any
+		err = handler(&CallInfo{
+			Endpoint:    endpoint,
+			Region:      c.getRegion(),
+			Request:     req,
+			RequestBody: requestBody,
+			ExpectCode:  expectCode,
+			Attempt:     attempt,
+			Result:      v,
+		})
+		if err == nil {
+			return nil
+		}
 
-	// actually do the request and parse errors if any
-	if err := c.do(req, expectCode, v); err != nil {
-		return err
-	}
+		wait, retry := policy.retry(ctx, method, attempt, err)
+		if !retry {
+			return err
+		}
 
-	return nil
+		if logger := c.getLogger(); logger != nil {
+			logger.DebugContext(
+				ctx,
+				"twizo retry",
+				"endpoint", endpoint,
+				"method", req.Method,
+				"url", c.logURL(req.URL),
+				"attempt", attempt+1,
+				"max_attempts", policy.MaxAttempts,
+				"wait", wait,
+				"error", c.logError(err),
+			)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
 }
 
 // Do is used by Call to execute an API request and parse the response. It uses
 // the backend's HTTP client to execute the request and unmarshals the response
-// into v. It also handles unmarshaling errors returned by the API.
-func (c *HTTPClient) do(request *http.Request, expectCode int, response interface{}) error {
+// into info.Result. It also handles unmarshaling errors returned by the API.
+func (c *Client) do(info *CallInfo) error {
+	logger := c.getLogger()
+	if logger != nil {
+		args := []interface{}{
+			"endpoint", info.Endpoint,
+			"method", info.Request.Method,
+			"url", c.logURL(info.Request.URL),
+			"attempt", info.Attempt,
+		}
+		if len(info.RequestBody) > 0 {
+			args = append(args, "body", c.logBody(info.RequestBody))
+		}
+		logger.DebugContext(info.Request.Context(), "twizo request", args...)
+	}
+
 	start := time.Now()
 
-	res, err := c.HTTPClient.Do(request)
+	res, err := c.getHTTPClient().Do(info.Request)
 
 	if err != nil {
+		if logger != nil {
+			logger.DebugContext(
+				info.Request.Context(),
+				"twizo request failed",
+				"endpoint", info.Endpoint,
+				"method", info.Request.Method,
+				"url", c.logURL(info.Request.URL),
+				"latency", time.Since(start),
+				"error", c.logError(err),
+			)
+		}
 		return err
 	}
 	defer res.Body.Close() // nolint: errcheck
 
 	// might want to use json.Decoder instead of ioutl.ReadAll -> sending to Unmarshal
+	info.StatusCode = res.StatusCode
 	resBody, err := ioutil.ReadAll(res.Body)
 	if err != nil {
 		return err
 	}
-
-	if len(resBody) > 0 {
-		DebugLogger.Printf("Response in [%v] with [%d] body %s", time.Since(start), res.StatusCode, resBody)
-	} else {
-		DebugLogger.Printf("Response in [%v] with [%d]", time.Since(start), res.StatusCode)
+	info.ResponseBody = resBody
+
+	if logger != nil {
+		args := []interface{}{
+			"endpoint", info.Endpoint,
+			"method", info.Request.Method,
+			"url", c.logURL(info.Request.URL),
+			"status", res.StatusCode,
+			"latency", time.Since(start),
+		}
+		if messageID := info.MessageID(); messageID != "" {
+			args = append(args, "message_id", messageID)
+		}
+		if len(resBody) > 0 {
+			args = append(args, "body", c.logBody(resBody))
+		}
+		logger.DebugContext(info.Request.Context(), "twizo response", args...)
 	}
 
 	// check if there was a problem
@@ -218,27 +428,30 @@ func (c *HTTPClient) do(request *http.Request, expectCode int, response interfac
 		if err := json.Unmarshal(resBody, apiError); err != nil {
 			return err
 		}
+		apiError.retryAfter = parseRetryAfter(res.Header.Get("Retry-After"))
 		return apiError
 	}
 
 	// check if the response code was something we expected
-	if res.StatusCode != expectCode {
+	if res.StatusCode != info.ExpectCode {
 		clientError := &ClientError{
-			Message: fmt.Sprintf("Unexpected response [%s]", resBody),
-			Code:    res.StatusCode,
+			Message:    fmt.Sprintf("Unexpected response [%s]", resBody),
+			Code:       res.StatusCode,
+			RetryAfter: parseRetryAfter(res.Header.Get("Retry-After")),
+			body:       resBody,
 		}
 		return clientError
 	}
 
 	// todo check if we are actually a http NoContent here ?
-	if response == nil {
+	if info.Result == nil {
 		// not expecting result we are done
 		return nil
 	}
 
 	// https://ahmetalpbalkan.com/blog/golang-json-decoder-pitfalls/
 	// todo: check the content-type to make sure it's application/json
-	if err := json.Unmarshal(resBody, response); err != nil {
+	if err := json.Unmarshal(resBody, info.Result); err != nil {
 		return err
 	}
 
@@ -247,7 +460,7 @@ func (c *HTTPClient) do(request *http.Request, expectCode int, response interfac
 
 // GetClient gets the a client initialized with region and key
 func GetClient(region APIRegion, key string) *HTTPClient {
-	return &HTTPClient{region, key, GetHTTPClient()}
+	return NewClient(key, region)
 }
 
 //
@@ -259,6 +472,11 @@ type HATEOASHref struct {
 	Href url.URL `json:"href"`
 }
 
+// MarshalJSON the HATEOASHref link
+func (l HATEOASHref) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]string{"href": l.Href.String()})
+}
+
 // UnmarshalJSON the HATEOASHref link
 func (l *HATEOASHref) UnmarshalJSON(j []byte) error {
 	var rawStrings map[string]string
@@ -361,6 +579,31 @@ func GetRegions() map[APIRegion]string {
 	return regionUrls
 }
 
+// validateValidity returns an error when validity is not a whole amount of seconds between min and max
+func validateValidity(validity time.Duration, min time.Duration, max time.Duration) error {
+	if validity%time.Second != 0 {
+		return fmt.Errorf("validity [%s] should be a whole amount of seconds", validity)
+	}
+	if validity < min || validity > max {
+		return fmt.Errorf("validity [%s] should be between [%s] and [%s]", validity, min, max)
+	}
+	return nil
+}
+
+// parseTimestamp parses the ISO-8601 timestamps returned by the api
+func parseTimestamp(timestamp *string) (*time.Time, error) {
+	if timestamp == nil || *timestamp == "" {
+		return nil, nil
+	}
+
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05Z0700", "2006-01-02 15:04:05"} {
+		if t, err := time.Parse(layout, *timestamp); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid timestamp [%s]", *timestamp)
+}
+
 func isDcsBinary(i int) bool {
 	if i&200 == 0 || i&248 == 240 {
 		return (i & 4) > 0
@@ -7,10 +7,12 @@ import (
 	. "github.com/twizoapi/lib-api-go/testing"
 
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 
 	"gopkg.in/jarcoal/httpmock.v1"
 )
@@ -133,3 +135,101 @@ func TestReceivingUnexpectedCodeRequests(t *testing.T) {
 		return
 	}
 }
+
+func TestClientsAreIndependent(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	clients := []*twizo.Client{
+		twizo.NewClient("key-eu", twizo.APIRegionEU),
+		twizo.NewClient("key-asia", twizo.APIRegionAsia),
+	}
+
+	for _, client := range clients {
+		expectAuth := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", twizo.ClientAuthUser, client.Key)))
+		err := HTTPMockSend(
+			http.MethodGet,
+			fmt.Sprintf("https://%s/%s/wallet/getbalance", twizo.GetHostForRegion(client.Region), twizo.ClientAPIVersion),
+			http.StatusOK,
+			[]byte(`{"credit":1,"currencyCode":"eur","freeVerifications":0,"wallet":"wallet"}`),
+			func(req *http.Request) error {
+				if req.Header.Get("Authorization") != fmt.Sprintf("Basic %s", expectAuth) {
+					return fmt.Errorf("invalid Authorization header got [%v]", req.Header.Get("Authorization"))
+				}
+				return nil
+			},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, client := range clients {
+		if _, err := client.BalanceGet(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestClientBaseURL(t *testing.T) {
+	baseURL, err := url.Parse("http://localhost:8080/twizo/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := twizo.NewClient(TestAPIKey, TestRegion)
+	client.BaseURL = baseURL
+
+	apiURL, err := twizo.GetURLFor("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, err := client.NewRequest(http.MethodGet, apiURL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectURL := fmt.Sprintf("http://localhost:8080/twizo/%s/test", twizo.ClientAPIVersion)
+	if req.URL.String() != expectURL {
+		t.Fatalf("Incorrect URL expecting [%s] got [%v]", expectURL, req.URL.String())
+	}
+
+	if apiURL.Host != "" {
+		t.Fatalf("NewRequest should not modify the url expecting [] got [%v]", apiURL.Host)
+	}
+}
+
+func TestCallContextCancelled(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	called := false
+	err := HTTPMockSend(
+		http.MethodGet,
+		fmt.Sprintf("https://%s/%s/wallet/getbalance", twizo.GetHostForRegion(twizo.RegionCurrent), twizo.ClientAPIVersion),
+		http.StatusOK,
+		[]byte(`{"credit":1,"currencyCode":"eur","freeVerifications":0,"wallet":"wallet"}`),
+		func(req *http.Request) error {
+			called = true
+			return nil
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	response, err := twizo.NewBalanceGetRequest().SubmitContext(ctx)
+	if response != nil {
+		t.Fatalf("Expecting nil response got [%#v]", response)
+	}
+	if err != context.Canceled {
+		t.Fatalf("Invalid error expecting [context.Canceled] got [%#v]", err)
+	}
+	if called {
+		t.Fatal("Cancelled context should not reach the server")
+	}
+}
@@ -0,0 +1,277 @@
+// Package twizootel instruments the twizo client with OpenTelemetry, every call
+// made by an instrumented client gets a span and is recorded in the metrics.
+//
+//	client := twizo.NewClient(key, twizo.APIRegionEU)
+//	twizootel.Instrument(client)
+//
+// It is a separate package so the twizo package itself does not depend on
+// OpenTelemetry.
+package twizootel
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	twizo "github.com/twizoapi/lib-api-go"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope of the tracer and meter
+const ScopeName = "github.com/twizoapi/lib-api-go/twizootel"
+
+// Attribute keys set on spans and metrics
+const (
+	AttributeEndpoint      = attribute.Key("twizo.endpoint")
+	AttributeRegion        = attribute.Key("twizo.region")
+	AttributeMessageID     = attribute.Key("twizo.message_id")
+	AttributeErrorCode     = attribute.Key("twizo.error_code")
+	AttributeAttempt       = attribute.Key("twizo.attempt")
+	AttributeCurrency      = attribute.Key("twizo.currency")
+	AttributeStatusCode    = attribute.Key("http.response.status_code")
+	AttributeRequestMethod = attribute.Key("http.request.method")
+)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+	propagator     propagation.TextMapPropagator
+}
+
+// Option configures the instrumentation
+type Option func(*config)
+
+// WithTracerProvider sets the tracer provider, the global one is used by default
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = provider
+	}
+}
+
+// WithMeterProvider sets the meter provider, the global one is used by default
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = provider
+	}
+}
+
+// WithPropagator sets the propagator used to inject the trace context into the
+// request headers, the global one is used by default
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagator = propagator
+	}
+}
+
+type instrumentation struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+	requests   metric.Int64Counter
+	duration   metric.Float64Histogram
+	salesPrice metric.Float64Histogram
+}
+
+// Instrument adds the instrumentation middleware to client
+func Instrument(client *twizo.Client, opts ...Option) error {
+	middleware, err := Middleware(opts...)
+	if err != nil {
+		return err
+	}
+
+	client.Middleware = append(client.Middleware, middleware)
+	return nil
+}
+
+// Middleware returns the middleware that creates a span per call, named after
+// the endpoint (sms.submit, verification.verify, ...), and records:
+//
+//	twizo.client.requests    the amount of calls
+//	twizo.client.duration    the duration of the calls in seconds
+//	twizo.client.sales_price the sales price of the messages in the responses, status and poll
+//	                         calls report the price of the same message again, filter on twizo.endpoint
+func Middleware(opts ...Option) (twizo.Middleware, error) {
+	c := &config{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.tracerProvider == nil {
+		c.tracerProvider = otel.GetTracerProvider()
+	}
+	if c.meterProvider == nil {
+		c.meterProvider = otel.GetMeterProvider()
+	}
+	if c.propagator == nil {
+		c.propagator = otel.GetTextMapPropagator()
+	}
+
+	meter := c.meterProvider.Meter(ScopeName)
+	i := &instrumentation{
+		tracer:     c.tracerProvider.Tracer(ScopeName),
+		propagator: c.propagator,
+	}
+
+	var err error
+	i.requests, err = meter.Int64Counter(
+		"twizo.client.requests",
+		metric.WithDescription("The amount of calls made to the twizo api"),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	i.duration, err = meter.Float64Histogram(
+		"twizo.client.duration",
+		metric.WithDescription("The duration of calls made to the twizo api"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	i.salesPrice, err = meter.Float64Histogram(
+		"twizo.client.sales_price",
+		metric.WithDescription("The sales price of the messages returned by the twizo api"),
+		metric.WithUnit("{currency}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return i.middleware, nil
+}
+
+func (i *instrumentation) middleware(next twizo.CallHandler) twizo.CallHandler {
+	return func(info *twizo.CallInfo) error {
+		name := string(info.Endpoint)
+		if name == "" {
+			name = "twizo.call"
+		}
+
+		attributes := []attribute.KeyValue{
+			AttributeEndpoint.String(string(info.Endpoint)),
+			AttributeRegion.String(string(info.Region)),
+			AttributeRequestMethod.String(info.Request.Method),
+		}
+
+		ctx, span := i.tracer.Start(
+			info.Request.Context(),
+			name,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(attributes...),
+			trace.WithAttributes(AttributeAttempt.Int(info.Attempt)),
+		)
+		defer span.End()
+
+		info.Request = info.Request.WithContext(ctx)
+		i.propagator.Inject(ctx, propagation.HeaderCarrier(info.Request.Header))
+
+		start := time.Now()
+		err := next(info)
+		elapsed := time.Since(start)
+
+		if info.StatusCode != 0 {
+			statusCode := AttributeStatusCode.Int(info.StatusCode)
+			attributes = append(attributes, statusCode)
+			span.SetAttributes(statusCode)
+		}
+		if code := errorCode(err); code != twizo.APIErrorCodeNone {
+			span.SetAttributes(AttributeErrorCode.Int(int(code)))
+		}
+		if messageID := info.MessageID(); messageID != "" {
+			span.SetAttributes(AttributeMessageID.String(messageID))
+		}
+
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		} else if info.StatusCode >= http.StatusBadRequest {
+			span.SetStatus(codes.Error, http.StatusText(info.StatusCode))
+		}
+
+		set := metric.WithAttributes(attributes...)
+		i.requests.Add(ctx, 1, set)
+		i.duration.Record(ctx, elapsed.Seconds(), set)
+
+		if err == nil {
+			for _, price := range salesPrices(info.ResponseBody) {
+				i.salesPrice.Record(
+					ctx,
+					price.amount,
+					metric.WithAttributes(
+						AttributeEndpoint.String(string(info.Endpoint)),
+						AttributeRegion.String(string(info.Region)),
+						AttributeCurrency.String(price.currency),
+					),
+				)
+			}
+		}
+
+		return err
+	}
+}
+
+// errorCode returns the api error code of err, if any
+func errorCode(err error) twizo.APIErrorCode {
+	var validationError *twizo.APIValidationError
+	if errors.As(err, &validationError) {
+		return validationError.ErrorCode()
+	}
+	var apiError *twizo.APIError
+	if errors.As(err, &apiError) {
+		return apiError.ErrorCode()
+	}
+	return twizo.APIErrorCodeNone
+}
+
+type salesPrice struct {
+	amount   float64
+	currency string
+}
+
+// salesPrices returns the sales prices found in body, responses contain one
+// message or a list of messages in _embedded
+func salesPrices(body []byte) []salesPrice {
+	if len(body) == 0 {
+		return nil
+	}
+
+	var v interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&v); err != nil {
+		return nil
+	}
+
+	var prices []salesPrice
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch value := v.(type) {
+		case map[string]interface{}:
+			if amount, ok := value["salesPrice"].(json.Number); ok {
+				if f, err := amount.Float64(); err == nil {
+					currency, _ := value["salesPriceCurrencyCode"].(string)
+					prices = append(prices, salesPrice{amount: f, currency: currency})
+				}
+			}
+			for _, item := range value {
+				walk(item)
+			}
+		case []interface{}:
+			for _, item := range value {
+				walk(item)
+			}
+		}
+	}
+	walk(v)
+
+	return prices
+}
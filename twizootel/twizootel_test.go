@@ -0,0 +1,146 @@
+package twizootel_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	twizo "github.com/twizoapi/lib-api-go"
+	. "github.com/twizoapi/lib-api-go/testing"
+	"github.com/twizoapi/lib-api-go/twizootel"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+func TestInstrument(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	HTTPMockSend(
+		http.MethodPost,
+		fmt.Sprintf("https://%s/%s/verification/submit", twizo.GetHostForRegion(TestRegion), twizo.ClientAPIVersion),
+		http.StatusCreated,
+		`{"messageId":"messageId","salesPrice":0.05,"salesPriceCurrencyCode":"EUR"}`,
+		nil,
+	)
+
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+
+	client := twizo.NewClient(TestAPIKey, TestRegion)
+	err := twizootel.Instrument(
+		client,
+		twizootel.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))),
+		twizootel.WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.VerificationSubmit("31600000000"); err != nil {
+		t.Fatal(err)
+	}
+
+	ended := spans.Ended()
+	if len(ended) != 1 {
+		t.Fatalf("Invalid amount of spans expecting [1] got [%d]", len(ended))
+	}
+	if ended[0].Name() != string(twizo.EndpointVerificationSubmit) {
+		t.Fatalf("Invalid span name expecting [%s] got [%s]", twizo.EndpointVerificationSubmit, ended[0].Name())
+	}
+
+	attributes := map[string]string{}
+	for _, attribute := range ended[0].Attributes() {
+		attributes[string(attribute.Key)] = attribute.Value.Emit()
+	}
+	expect := map[string]string{
+		"twizo.region":              TestRegion,
+		"twizo.message_id":          "messageId",
+		"http.response.status_code": "201",
+	}
+	for key, value := range expect {
+		if attributes[key] != value {
+			t.Fatalf("Invalid attribute [%s] expecting [%s] got [%s]", key, value, attributes[key])
+		}
+	}
+
+	var data metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &data); err != nil {
+		t.Fatal(err)
+	}
+
+	found := map[string]bool{}
+	for _, scope := range data.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			found[m.Name] = true
+			if sum, ok := m.Data.(metricdata.Sum[int64]); ok && sum.DataPoints[0].Value != 1 {
+				t.Fatalf("Invalid request count expecting [1] got [%d]", sum.DataPoints[0].Value)
+			}
+			if histogram, ok := m.Data.(metricdata.Histogram[float64]); ok && m.Name == "twizo.client.sales_price" {
+				if histogram.DataPoints[0].Sum != 0.05 {
+					t.Fatalf("Invalid sales price expecting [0.05] got [%v]", histogram.DataPoints[0].Sum)
+				}
+			}
+		}
+	}
+	for _, name := range []string{"twizo.client.requests", "twizo.client.duration", "twizo.client.sales_price"} {
+		if !found[name] {
+			t.Fatalf("Expecting metric [%s] to be recorded", name)
+		}
+	}
+}
+
+func TestInstrumentErrorCode(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(
+		http.MethodGet,
+		fmt.Sprintf(
+			"https://%s/%s/verification/submit/messageId",
+			twizo.GetHostForRegion(TestRegion),
+			twizo.ClientAPIVersion,
+		),
+		func(req *http.Request) (*http.Response, error) {
+			resp := httpmock.NewStringResponse(
+				http.StatusLocked,
+				`{"title":"Locked","status":423,"detail":"Token expired","errorCode":102}`,
+			)
+			resp.Header.Set("Content-Type", "application/problem+json")
+			return resp, nil
+		},
+	)
+
+	spans := tracetest.NewSpanRecorder()
+	client := twizo.NewClient(TestAPIKey, TestRegion)
+	err := twizootel.Instrument(
+		client,
+		twizootel.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.VerificationStatus("messageId"); err == nil {
+		t.Fatal("Expecting error got [nil]")
+	}
+
+	ended := spans.Ended()
+	if len(ended) != 1 {
+		t.Fatalf("Invalid amount of spans expecting [1] got [%d]", len(ended))
+	}
+	for _, attribute := range ended[0].Attributes() {
+		if attribute.Key == twizootel.AttributeErrorCode {
+			if attribute.Value.AsInt64() != 102 {
+				t.Fatalf("Invalid error code expecting [102] got [%d]", attribute.Value.AsInt64())
+			}
+			return
+		}
+	}
+	t.Fatal("Expecting error code attribute on span")
+}
@@ -1,10 +1,12 @@
 package twizo
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 )
 
@@ -65,12 +67,23 @@ func (vT *VerificationTypes) UnmarshalJSON(j []byte) error {
 
 // Fetch retrieves the valid verification types for the application
 func (vT *VerificationTypes) Fetch() error {
+	return vT.FetchContext(context.Background())
+}
+
+// FetchContext retrieves the valid verification types using ctx for the call
+func (vT *VerificationTypes) FetchContext(ctx context.Context) error {
+	return vT.fetch(ctx, DefaultClient)
+}
+
+func (vT *VerificationTypes) fetch(ctx context.Context, client *Client) error {
 	// todo: this should be array according to documentation
 	response := &VerificationTypes{}
 
 	apiURL, _ := GetURLFor("application/verification_types")
 
-	err := GetClient(RegionCurrent, APIKey).Call(
+	err := client.call(
+		ctx,
+		EndpointVerificationTypes,
 		http.MethodGet,
 		apiURL,
 		nil,
@@ -136,6 +149,14 @@ const (
 	VerificationTokenFailed          VerificationStatusCode = 104
 )
 
+const (
+	// VerificationValidityMin is the minimum validity of a verification token
+	VerificationValidityMin = 1 * time.Second
+
+	// VerificationValidityMax is the maximum validity of a verification token
+	VerificationValidityMax = 24 * time.Hour
+)
+
 // VerificationRequest contains the struct of the request send for a verification
 type VerificationRequest struct {
 	recipient        Recipient
@@ -150,7 +171,8 @@ type VerificationRequest struct {
 	tokenLength      int
 	tokenType        VerificationTokenType
 	verificationType VerificationType
-	validity         string
+	validity         time.Duration
+	client           *Client
 }
 
 type jsonVerificationRequest struct {
@@ -166,7 +188,7 @@ type jsonVerificationRequest struct {
 	TokenLength      int                   `json:"tokenLength,omitempty"`
 	TokenType        VerificationTokenType `json:"tokenType,omitempty"`
 	VerificationType VerificationType      `json:"type,omitempty"`
-	Validity         string                `json:"validity,omitempty"`
+	Validity         int                   `json:"validity,omitempty"`
 }
 
 func (request *jsonVerificationRequest) copyFrom(r *VerificationRequest) {
@@ -177,11 +199,12 @@ func (request *jsonVerificationRequest) copyFrom(r *VerificationRequest) {
 	request.Sender = r.sender
 	request.SenderTon = r.senderTon
 	request.SenderNpi = r.senderNpi
+	request.SessionID = r.sessionID
 	request.Tag = r.tag
 	request.TokenType = r.tokenType
 	request.TokenLength = r.tokenLength
 	request.VerificationType = r.verificationType
-	request.Validity = r.validity
+	request.Validity = int(r.validity / time.Second)
 }
 
 // MarshalJSON is used to convert SmsRequest to json
@@ -191,9 +214,15 @@ func (request *VerificationRequest) MarshalJSON() ([]byte, error) {
 	return json.Marshal(jsonRequest)
 }
 
-// SetRecipient set the recipient of the verification
-func (request *VerificationRequest) SetRecipient(recipient Recipient) {
-	request.recipient = recipient
+// SetRecipient sets the recipient of the verification, it is validated and normalised using ParseRecipient
+func (request *VerificationRequest) SetRecipient(recipient Recipient) error {
+	parsed, err := ParseRecipient(string(recipient), clientOrDefault(request.client).getDefaultCountry())
+	if err != nil {
+		return err
+	}
+	request.recipient = parsed
+
+	return nil
 }
 
 // GetRecipient get the recipient of the verification
@@ -261,6 +290,16 @@ func (request VerificationRequest) GetSenderTon() int {
 	return request.senderTon
 }
 
+// SetSessionID sets the session id of the verification
+func (request *VerificationRequest) SetSessionID(sessionID string) {
+	request.sessionID = sessionID
+}
+
+// GetSessionID gets the session id of the verification
+func (request VerificationRequest) GetSessionID() string {
+	return request.sessionID
+}
+
 // SetTag set the tag for this verification
 func (request *VerificationRequest) SetTag(tag string) {
 	request.tag = tag
@@ -301,19 +340,31 @@ func (request VerificationRequest) GetVerificationType() VerificationType {
 	return request.verificationType
 }
 
-// SetValidity set the validity of the verification request, as amount of seconds
-// todo: this should be integer
-func (request *VerificationRequest) SetValidity(validity string) {
+// SetValidity set how long the token of the verification request is valid, it should be whole seconds
+// between VerificationValidityMin and VerificationValidityMax, 0 uses the default of the api
+func (request *VerificationRequest) SetValidity(validity time.Duration) error {
+	if validity != 0 {
+		if err := validateValidity(validity, VerificationValidityMin, VerificationValidityMax); err != nil {
+			return err
+		}
+	}
 	request.validity = validity
+
+	return nil
 }
 
-// GetValidity gets the validity of a verification
-func (request VerificationRequest) GetValidity() string {
+// GetValidity gets the validity of a verification, 0 when not set
+func (request VerificationRequest) GetValidity() time.Duration {
 	return request.validity
 }
 
 // Submit wil submit the verification request
 func (request *VerificationRequest) Submit() (*VerificationResponse, error) {
+	return request.SubmitContext(context.Background())
+}
+
+// SubmitContext submits the verification request using ctx for the call
+func (request *VerificationRequest) SubmitContext(ctx context.Context) (*VerificationResponse, error) {
 	response := &VerificationResponse{}
 
 	apiURL, err := GetURLFor("verification/submit")
@@ -321,7 +372,10 @@ func (request *VerificationRequest) Submit() (*VerificationResponse, error) {
 		return nil, err
 	}
 
-	err = GetClient(RegionCurrent, APIKey).Call(
+	client := clientOrDefault(request.client)
+	err = client.call(
+		ctx,
+		EndpointVerificationSubmit,
 		http.MethodPost,
 		apiURL,
 		request,
@@ -331,6 +385,11 @@ func (request *VerificationRequest) Submit() (*VerificationResponse, error) {
 	if err != nil {
 		return nil, err
 	}
+	response.client = client
+
+	// kept to resend the verification with the same parameters
+	submitted := *request
+	response.request = &submitted
 
 	return response, nil
 }
@@ -369,6 +428,8 @@ type VerificationResponse struct {
 	voiceSentence          *string
 	webHook                *string
 	links                  HATEOASLinks
+	request                *VerificationRequest
+	client                 *Client
 }
 
 type jsonVerificationResponse struct {
@@ -557,9 +618,16 @@ func (response VerificationResponse) GetValidUntilDateTime() time.Time {
 
 // Status Retrieve the status of the validation
 func (response *VerificationResponse) Status() error {
-	newResponse := &VerificationResponse{}
+	return response.StatusContext(context.Background())
+}
+
+// StatusContext retrieves the status of the validation using ctx for the call
+func (response *VerificationResponse) StatusContext(ctx context.Context) error {
+	newResponse := &VerificationResponse{request: response.request, client: response.client}
 
-	err := GetClient(RegionCurrent, APIKey).Call(
+	err := clientOrDefault(response.client).call(
+		ctx,
+		EndpointVerificationStatus,
 		http.MethodGet,
 		&response.links.Self.Href,
 		nil,
@@ -577,7 +645,12 @@ func (response *VerificationResponse) Status() error {
 
 // Verify verifies the token entered for a verification
 func (response *VerificationResponse) Verify(token string) error {
-	newResponse := &VerificationResponse{}
+	return response.VerifyContext(context.Background(), token)
+}
+
+// VerifyContext verifies the token entered for a verification using ctx for the call
+func (response *VerificationResponse) VerifyContext(ctx context.Context, token string) error {
+	newResponse := &VerificationResponse{request: response.request, client: response.client}
 
 	// to validate we need to add a query token=<token>
 	newResponse.links = response.links.getDeepClone()
@@ -586,7 +659,9 @@ func (response *VerificationResponse) Verify(token string) error {
 	q.Add("token", token)
 	newResponse.links.Self.Href.RawQuery = q.Encode()
 
-	err := GetClient(RegionCurrent, APIKey).Call(
+	err := clientOrDefault(response.client).call(
+		ctx,
+		EndpointVerificationVerify,
 		http.MethodGet,
 		&newResponse.links.Self.Href,
 		nil,
@@ -594,33 +669,93 @@ func (response *VerificationResponse) Verify(token string) error {
 		newResponse,
 	)
 
-	switch apiError := err.(type) {
-	case *APIError:
-		if apiError.Status() == http.StatusUnprocessableEntity &&
-			VerificationStatusCode(apiError.ErrorCode()) == VerificationTokenInvalid {
-			response.statusCode = VerificationTokenInvalid
-		} else if apiError.Status() == http.StatusLocked &&
-			VerificationStatusCode(apiError.ErrorCode()) == VerificationTokenExpired {
-			response.statusCode = VerificationTokenExpired
-		} else if apiError.Status() == http.StatusLocked &&
-			VerificationStatusCode(apiError.ErrorCode()) == VerificationTokenAlreadyVerified {
-			response.statusCode = VerificationTokenAlreadyVerified
-		} else if apiError.Status() == http.StatusLocked &&
-			VerificationStatusCode(apiError.ErrorCode()) == VerificationTokenFailed {
-			response.statusCode = VerificationTokenFailed
-		} else {
+	if err != nil {
+		statusCode, ok := verificationStatusFromError(err)
+		if !ok {
 			// undocumented response, error out
 			return err
 		}
+		response.statusCode = statusCode
 		return nil
 	}
 
-	if err == nil {
-		// no error use response to override ourselves
-		*response = *newResponse
+	// no error use response to override ourselves
+	*response = *newResponse
+
+	return nil
+}
+
+// Resend submits a new verification with the parameters of this verification
+func (response *VerificationResponse) Resend() (*VerificationResponse, error) {
+	return response.ResendContext(context.Background())
+}
+
+// ResendContext submits a new verification with the parameters of this verification using ctx for the
+// call. A verification that was not submitted by this process, example retrieved by VerificationStatus,
+// is resent using the parameters the api returned.
+func (response *VerificationResponse) ResendContext(ctx context.Context) (*VerificationResponse, error) {
+	request, err := response.resendRequest()
+	if err != nil {
+		return nil, err
 	}
+	return request.SubmitContext(ctx)
+}
 
-	return err
+// resendRequest returns a copy of the request the verification was submitted with, or a request with the
+// parameters of the response when there is none
+func (response *VerificationResponse) resendRequest() (*VerificationRequest, error) {
+	if response.request != nil {
+		request := *response.request
+		return &request, nil
+	}
+	if response.recipient == "" {
+		return nil, fmt.Errorf("verification [%s] has no recipient to resend to", response.messageID)
+	}
+
+	request := &VerificationRequest{
+		recipient:        response.recipient,
+		bodyTemplate:     response.bodyTemplate,
+		dcs:              response.dcs,
+		language:         response.language,
+		sender:           response.sender,
+		senderNpi:        response.senderNpi,
+		senderTon:        response.senderTon,
+		sessionID:        response.sessionID,
+		tag:              response.tag,
+		tokenType:        VerificationTokenType(response.tokenType),
+		verificationType: VerificationType(response.verificationType),
+		validity:         time.Duration(response.validity) * time.Second,
+		client:           response.client,
+	}
+	if length, err := strconv.Atoi(response.tokenLength); err == nil {
+		request.tokenLength = length
+	}
+	return request, nil
+}
+
+// verificationStatusFromError maps the documented errors of a verify call onto the verification status
+func verificationStatusFromError(err error) (VerificationStatusCode, bool) {
+	apiError, ok := asAPIError(err)
+	if !ok {
+		return VerificationTokenUnknown, false
+	}
+
+	switch {
+	case apiError.Status() == http.StatusUnprocessableEntity &&
+		apiError.ErrorCode() == APIErrorCodeTokenInvalid:
+		return VerificationTokenInvalid, true
+	case apiError.Status() == http.StatusLocked &&
+		apiError.ErrorCode() == APIErrorCodeTokenExpired:
+		return VerificationTokenExpired, true
+	case apiError.Status() == http.StatusLocked &&
+		apiError.ErrorCode() == APIErrorCodeTokenAlreadyVerified:
+		return VerificationTokenAlreadyVerified, true
+	case apiError.Status() == http.StatusLocked &&
+		apiError.ErrorCode() == APIErrorCodeTokenFailed:
+		return VerificationTokenFailed, true
+	}
+
+	return VerificationTokenUnknown, false
 }
 
 // IsTokenUnknown is a helper function to check the token status is unknown
@@ -669,6 +804,12 @@ func (response stdVerificationResponse) IsTokenSuccess() bool {
 
 // NewVerificationRequest creates a new verificationParam using a recipient (the only required var)
 func NewVerificationRequest(recipient interface{}) (*VerificationRequest, error) {
+	return DefaultClient.NewVerificationRequest(recipient)
+}
+
+// NewVerificationRequest creates a new verificationParam using a recipient that will be
+// submitted using the client
+func (c *Client) NewVerificationRequest(recipient interface{}) (*VerificationRequest, error) {
 	r, err := convertRecipients(recipient)
 	if err != nil {
 		return nil, err
@@ -676,13 +817,21 @@ func NewVerificationRequest(recipient interface{}) (*VerificationRequest, error)
 	if len(r) != 1 {
 		return nil, fmt.Errorf("need exactly one [recipient] for NewVerificationRequest got [%d]", len(r))
 	}
-	params := &VerificationRequest{recipient: r[0]}
+	params := &VerificationRequest{client: c}
+	if err := params.SetRecipient(r[0]); err != nil {
+		return nil, err
+	}
 	return params, nil
 }
 
 // VerificationSubmit creates a verificationRequest from verificationParams and submits it
 func VerificationSubmit(recipient interface{}) (*VerificationResponse, error) {
-	verification, err := NewVerificationRequest(recipient)
+	return DefaultClient.VerificationSubmit(recipient)
+}
+
+// VerificationSubmit creates a verificationRequest and submits it using the client
+func (c *Client) VerificationSubmit(recipient interface{}) (*VerificationResponse, error) {
+	verification, err := c.NewVerificationRequest(recipient)
 	if err != nil {
 		return nil, err
 	}
@@ -691,33 +840,63 @@ func VerificationSubmit(recipient interface{}) (*VerificationResponse, error) {
 
 // VerificationStatus retrieves the status of the validation using the messageId
 func VerificationStatus(messageID string) (*VerificationResponse, error) {
+	return DefaultClient.VerificationStatus(messageID)
+}
+
+// VerificationStatus retrieves the status of the validation using the messageId and the client
+func (c *Client) VerificationStatus(messageID string) (*VerificationResponse, error) {
+	return c.VerificationStatusContext(context.Background(), messageID)
+}
+
+// VerificationStatusContext retrieves the status of the validation by messageId using ctx for the call
+func (c *Client) VerificationStatusContext(ctx context.Context, messageID string) (*VerificationResponse, error) {
 	apiURL, err := GetURLFor(fmt.Sprintf("verification/submit/%s", url.PathEscape(messageID)))
 	if err != nil {
 		return nil, err
 	}
 
-	request := &VerificationResponse{messageID: messageID, links: createSelfLinks(apiURL)}
-	err = request.Status()
+	request := &VerificationResponse{messageID: messageID, links: createSelfLinks(apiURL), client: c}
+	err = request.StatusContext(ctx)
 	return request, err
 }
 
 // VerificationVerify validates the result of the validation request using the messageId and the token
 func VerificationVerify(messageID string, token string) (*VerificationResponse, error) {
+	return DefaultClient.VerificationVerify(messageID, token)
+}
+
+// VerificationVerify validates the token of the verification with messageId using the client
+func (c *Client) VerificationVerify(messageID string, token string) (*VerificationResponse, error) {
+	return c.VerificationVerifyContext(context.Background(), messageID, token)
+}
+
+// VerificationVerifyContext validates the token of the verification with messageId using ctx for the call
+func (c *Client) VerificationVerifyContext(
+	ctx context.Context,
+	messageID string,
+	token string,
+) (*VerificationResponse, error) {
 	apiURL, err := GetURLFor(fmt.Sprintf("verification/submit/%s", url.PathEscape(messageID)))
 	if err != nil {
 		return nil, err
 	}
 
-	request := &VerificationResponse{messageID: messageID, links: createSelfLinks(apiURL)}
-	err = request.Verify(token)
+	request := &VerificationResponse{messageID: messageID, links: createSelfLinks(apiURL), client: c}
+	err = request.VerifyContext(ctx, token)
 	return request, err
 }
 
 // VerificationFetchTypes retrieves all verification types for the application
 // from the server
 func VerificationFetchTypes() (*VerificationTypes, error) {
+	return DefaultClient.VerificationFetchTypes()
+}
+
+// VerificationFetchTypes retrieves all verification types for the application
+// of the client
+func (c *Client) VerificationFetchTypes() (*VerificationTypes, error) {
 	v := &VerificationTypes{}
-	err := v.Fetch()
+	err := v.fetch(context.Background(), c)
 	if err != nil {
 		return nil, err
 	}
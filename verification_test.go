@@ -4,7 +4,9 @@ package twizo_test
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
+	"time"
 
 	twizo "github.com/twizoapi/lib-api-go"
 	. "github.com/twizoapi/lib-api-go/testing"
@@ -33,12 +35,12 @@ func TestVerificationInvalidJsonResponse(t *testing.T) {
 
 func TestVerificationNew(t *testing.T) {
 	// from string
-	_, err := twizo.NewVerificationRequest("0000000000")
+	_, err := twizo.NewVerificationRequest("31600000000")
 	if err != nil {
 		t.Fatal(err)
 	}
 	// from recipient
-	_, err = twizo.NewVerificationRequest(twizo.Recipient("0000000000"))
+	_, err = twizo.NewVerificationRequest(twizo.Recipient("31600000000"))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -80,7 +82,7 @@ func TestVerificationSubmit(t *testing.T) {
 		},
 	)
 
-	response, err := twizo.VerificationSubmit(twizo.Recipient("0000000000"))
+	response, err := twizo.VerificationSubmit(twizo.Recipient("31600000000"))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -114,7 +116,21 @@ func TestVerificationSubmitAdvanced(t *testing.T) {
 		},
 	)
 
-	request, _ := twizo.NewVerificationRequest(twizo.Recipient("0000000000"))
+	request, _ := twizo.NewVerificationRequest(twizo.Recipient("31600000000"))
+	if err := request.SetValidity(twizo.VerificationValidityMax + time.Second); err == nil {
+		t.Fatal("Expecting error for validity above the maximum got [nil]")
+	}
+	if err := request.SetValidity(2 * time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	j, err := json.Marshal(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(j), `"validity":120`) {
+		t.Fatalf("Invalid validity expecting [120] got [%s]", j)
+	}
 
 	response, err := request.Submit()
 	if err != nil {
@@ -192,3 +208,105 @@ func TestVerificationTypesDuplicates(t *testing.T) {
 		t.Fatal("invalid duplicate elements not present expecting [\"sms\"] got", string(json))
 	}
 }
+
+func TestVerificationVerifyErrorStatus(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	verificationURL := fmt.Sprintf(
+		"https://%s/%s/verification/submit/messageId",
+		twizo.GetHostForRegion(twizo.RegionCurrent),
+		twizo.ClientAPIVersion,
+	)
+
+	httpmock.RegisterResponder(
+		http.MethodPost,
+		fmt.Sprintf(
+			"https://%s/%s/verification/submit",
+			twizo.GetHostForRegion(twizo.RegionCurrent),
+			twizo.ClientAPIVersion,
+		),
+		func(req *http.Request) (*http.Response, error) {
+			resp := httpmock.NewStringResponse(
+				http.StatusCreated,
+				fmt.Sprintf(`{"messageId":"messageId","_links":{"self":{"href":"%s"}}}`, verificationURL),
+			)
+			resp.Header.Set("Content-Type", "application/json")
+			return resp, nil
+		},
+	)
+
+	tests := []struct {
+		status    int
+		errorCode int
+		expect    twizo.VerificationStatusCode
+	}{
+		{http.StatusUnprocessableEntity, 103, twizo.VerificationTokenInvalid},
+		{http.StatusLocked, 102, twizo.VerificationTokenExpired},
+		{http.StatusLocked, 101, twizo.VerificationTokenAlreadyVerified},
+		{http.StatusLocked, 104, twizo.VerificationTokenFailed},
+	}
+
+	for _, test := range tests {
+		httpmock.RegisterResponder(
+			http.MethodGet,
+			verificationURL+"?token=12345",
+			newProblemResponder(test.status, test.errorCode),
+		)
+
+		response, err := twizo.VerificationSubmit("31600000000")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := response.Verify("12345"); err != nil {
+			t.Fatalf("Expecting no error for status [%d:%d] got [%#v]", test.status, test.errorCode, err)
+		}
+		if response.GetStatusCode() != test.expect {
+			t.Fatalf(
+				"Invalid status code for [%d:%d] expecting [%d] got [%d]",
+				test.status,
+				test.errorCode,
+				test.expect,
+				response.GetStatusCode(),
+			)
+		}
+	}
+
+	// undocumented error code, should be returned as error
+	httpmock.RegisterResponder(
+		http.MethodGet,
+		verificationURL+"?token=12345",
+		newProblemResponder(http.StatusLocked, 1),
+	)
+
+	response, err := twizo.VerificationSubmit("31600000000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = response.Verify("12345")
+	apiError, ok := err.(*twizo.APIError)
+	if !ok {
+		t.Fatalf("Invalid error expecting [twizo.APIError] got [%#v]", err)
+	}
+	if apiError.ErrorCode() != 1 {
+		t.Fatalf("Invalid error code expecting [1] got [%d]", apiError.ErrorCode())
+	}
+}
+
+// newProblemResponder responds with an application/problem+json error containing errorCode
+func newProblemResponder(status int, errorCode int) httpmock.Responder {
+	return func(req *http.Request) (*http.Response, error) {
+		resp := httpmock.NewStringResponse(
+			status,
+			fmt.Sprintf(
+				`{"type":"http://www.w3.org/Protocols/rfc2616/rfc2616-sec10.html",`+
+					`"title":"%s","status":%d,"detail":"Token failed","errorCode":%d}`,
+				http.StatusText(status),
+				status,
+				errorCode,
+			),
+		)
+		resp.Header.Set("Content-Type", "application/problem+json")
+		return resp, nil
+	}
+}
@@ -0,0 +1,203 @@
+package twizo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrVerificationMaxSends is returned when the maximum amount of sends is reached
+	ErrVerificationMaxSends = errors.New("twizo: maximum amount of verification sends reached")
+
+	// ErrVerificationCooldown is matched by the error of a send within the cooldown, see VerificationCooldownError
+	ErrVerificationCooldown = errors.New("twizo: verification send within cooldown")
+
+	// ErrVerificationNotSent is returned when verifying a token before a verification was sent
+	ErrVerificationNotSent = errors.New("twizo: no verification sent")
+)
+
+// VerificationCooldownError is the error of a send within the cooldown
+type VerificationCooldownError struct {
+	// Wait is the time until the next send is allowed
+	Wait time.Duration
+}
+
+// Error returns the error message
+func (e *VerificationCooldownError) Error() string {
+	return fmt.Sprintf("verification send within cooldown, next send allowed in [%s]", e.Wait)
+}
+
+// Is makes errors.Is(err, ErrVerificationCooldown) match
+func (e *VerificationCooldownError) Is(target error) bool {
+	return target == ErrVerificationCooldown
+}
+
+// VerificationFlow sends a verification to a recipient using a preferred, ordered list of verification
+// types. When the submit of a type fails the next type is tried, every send after the first (a resend)
+// also moves on to the next type, the last type is used for all remaining resends. The flow keeps the
+// current verification, so the token is verified against the last verification that was sent. The sends
+// are limited per recipient by a VerificationResender.
+//
+// A VerificationFlow can be used from multiple goroutines, it implements Verifier.
+type VerificationFlow struct {
+	// Allowed are the types the application allows (see VerificationTypes.Fetch), types that are not
+	// allowed are skipped. All types are tried when nil.
+	Allowed VerificationTypes
+
+	resender *VerificationResender
+	request  *VerificationRequest
+	types    VerificationTypes
+
+	mu       sync.Mutex
+	next     VerificationType
+	sends    int
+	response *VerificationResponse
+}
+
+// NewVerificationFlow creates a new flow sending request using types in order of preference, the type of
+// request itself is ignored. The sends are limited by a new VerificationResender with the default limits,
+// so the limits only apply to the flow itself, use VerificationResender.NewVerificationFlow to share them.
+func NewVerificationFlow(request *VerificationRequest, types ...VerificationType) *VerificationFlow {
+	return clientOrDefault(request.client).NewVerificationResender().NewVerificationFlow(request, types...)
+}
+
+// NewVerificationFlow creates a new flow sending request using types in order of preference limited by
+// the resender, so all flows of the resender share the limits per recipient
+func (r *VerificationResender) NewVerificationFlow(
+	request *VerificationRequest,
+	types ...VerificationType,
+) *VerificationFlow {
+	return &VerificationFlow{
+		resender: r,
+		request:  request,
+		types:    types,
+	}
+}
+
+// Send sends the verification, or resends it using the next type
+func (f *VerificationFlow) Send() (*VerificationResponse, error) {
+	return f.SendContext(context.Background())
+}
+
+// SendContext sends the verification, or resends it using the next type, using ctx for the calls. The
+// error matches ErrVerificationMaxSends when the maximum is reached and ErrVerificationCooldown within
+// the minimum interval of the resender. When the submit of every remaining type fails the error of the
+// last type is returned.
+func (f *VerificationFlow) SendContext(ctx context.Context) (*VerificationResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	types := f.remainingTypes()
+	if len(types) == 0 {
+		return nil, errors.New("twizo: none of the verification types of the flow are allowed")
+	}
+
+	var lastErr error
+	for i, t := range types {
+		request := *f.request
+		request.SetVerificationType(t)
+
+		response, err := f.resender.SendContext(ctx, &request)
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, ErrVerificationMaxSends) || errors.Is(err, ErrVerificationCooldown) {
+				return nil, err
+			}
+			lastErr = err
+			continue
+		}
+
+		f.response = response
+		f.sends++
+		if i+1 < len(types) {
+			f.next = types[i+1]
+		} else {
+			f.next = t
+		}
+		return response, nil
+	}
+
+	return nil, fmt.Errorf("sending verification failed for all remaining types: %w", lastErr)
+}
+
+// remainingTypes returns the allowed types of the flow starting at the next type, the last allowed type
+// when none of the remaining types is allowed
+func (f *VerificationFlow) remainingTypes() VerificationTypes {
+	start := 0
+	for i, t := range f.types {
+		if t == f.next {
+			start = i
+			break
+		}
+	}
+
+	var types, allowed VerificationTypes
+	for i, t := range f.types {
+		if f.Allowed != nil && !f.Allowed.Has(t) {
+			continue
+		}
+		allowed = append(allowed, t)
+		if i >= start {
+			types = append(types, t)
+		}
+	}
+	if len(types) == 0 && len(allowed) > 0 {
+		return allowed[len(allowed)-1:]
+	}
+	return types
+}
+
+// Verify verifies the token against the current verification
+func (f *VerificationFlow) Verify(token string) (VerificationOutcome, error) {
+	return f.VerifyToken(context.Background(), token)
+}
+
+// VerifyToken verifies the token against the current verification using ctx for the call, the error is
+// ErrVerificationNotSent when nothing was sent yet
+func (f *VerificationFlow) VerifyToken(ctx context.Context, token string) (VerificationOutcome, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.response == nil {
+		return VerificationOutcome{}, ErrVerificationNotSent
+	}
+	return NewVerificationVerifier(f.response).VerifyToken(ctx, token)
+}
+
+// GetResponse returns the current verification, nil when nothing was sent yet
+func (f *VerificationFlow) GetResponse() *VerificationResponse {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.response
+}
+
+// GetMessageID returns the message id of the current verification, empty when nothing was sent yet
+func (f *VerificationFlow) GetMessageID() string {
+	if response := f.GetResponse(); response != nil {
+		return response.GetMessageID()
+	}
+	return ""
+}
+
+// GetVerificationType returns the type of the current verification, empty when nothing was sent yet
+func (f *VerificationFlow) GetVerificationType() VerificationType {
+	if response := f.GetResponse(); response != nil {
+		return VerificationType(response.GetVerificationType())
+	}
+	return ""
+}
+
+// GetSends returns the amount of successful sends of the flow
+func (f *VerificationFlow) GetSends() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.sends
+}
+
+// GetNextSendIn returns the time until the next send to the recipient is allowed, 0 when it is allowed now.
+// The error is ErrVerificationMaxSends when no more sends are allowed.
+func (f *VerificationFlow) GetNextSendIn() (time.Duration, error) {
+	return f.resender.NextResendIn(context.Background(), f.request.GetRecipient())
+}
@@ -0,0 +1,244 @@
+package twizo_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	twizo "github.com/twizoapi/lib-api-go"
+	. "github.com/twizoapi/lib-api-go/testing"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+func init() {
+	twizo.APIKey = TestAPIKey
+	twizo.RegionCurrent = TestRegion
+}
+
+// verificationSubmitURL returns the url of the verification with messageID, the submit url when empty
+func verificationSubmitURL(messageID string) string {
+	apiURL := fmt.Sprintf(
+		"https://%s/%s/verification/submit",
+		twizo.GetHostForRegion(twizo.RegionCurrent),
+		twizo.ClientAPIVersion,
+	)
+	if messageID != "" {
+		apiURL += "/" + messageID
+	}
+	return apiURL
+}
+
+// registerVerificationSubmit mocks the verification submit, the message id is "id-<type>" and submits of
+// the failing types return a 422. The returned function returns the submitted types.
+func registerVerificationSubmit(failing ...twizo.VerificationType) func() []twizo.VerificationType {
+	var submitted []twizo.VerificationType
+	httpmock.RegisterResponder(
+		http.MethodPost,
+		verificationSubmitURL(""),
+		func(req *http.Request) (*http.Response, error) {
+			body, err := ioutil.ReadAll(req.Body)
+			if err != nil {
+				return nil, err
+			}
+			request := struct {
+				Recipient string                 `json:"recipient"`
+				Type      twizo.VerificationType `json:"type"`
+			}{}
+			if err := json.Unmarshal(body, &request); err != nil {
+				return nil, err
+			}
+			submitted = append(submitted, request.Type)
+
+			for _, t := range failing {
+				if t == request.Type {
+					return newProblemResponder(http.StatusUnprocessableEntity, 0)(req)
+				}
+			}
+			messageID := "id-" + string(request.Type)
+			resp := httpmock.NewStringResponse(
+				http.StatusCreated,
+				fmt.Sprintf(
+					`{"messageId":"%s","recipient":"%s","type":"%s","_links":{"self":{"href":"%s"}}}`,
+					messageID,
+					request.Recipient,
+					request.Type,
+					verificationSubmitURL(messageID),
+				),
+			)
+			resp.Header.Set("Content-Type", "application/json")
+			return resp, nil
+		},
+	)
+	return func() []twizo.VerificationType { return submitted }
+}
+
+func TestVerificationFlow(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	submitted := registerVerificationSubmit(twizo.VerificationTypeSms)
+
+	request, err := twizo.NewVerificationRequest("31600000000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resender := twizo.NewVerificationResender()
+	resender.MinInterval = 20 * time.Millisecond
+	resender.MaxResends = 2
+	flow := resender.NewVerificationFlow(
+		request,
+		twizo.VerificationTypeSms,
+		twizo.VerificationTypeCall,
+		twizo.VerificationTypeTelegram,
+	)
+
+	if _, err := flow.Verify("12345"); err != twizo.ErrVerificationNotSent {
+		t.Fatalf("Invalid error expecting [%v] got [%v]", twizo.ErrVerificationNotSent, err)
+	}
+
+	// sms fails, falls back to call
+	if _, err := flow.Send(); err != nil {
+		t.Fatal(err)
+	}
+	if flow.GetMessageID() != "id-call" || flow.GetVerificationType() != twizo.VerificationTypeCall {
+		t.Fatalf("Invalid verification expecting [id-call] got [%s]", flow.GetMessageID())
+	}
+
+	_, err = flow.Send()
+	var cooldownErr *twizo.VerificationCooldownError
+	if !errors.Is(err, twizo.ErrVerificationCooldown) || !errors.As(err, &cooldownErr) || cooldownErr.Wait <= 0 {
+		t.Fatalf("Invalid error expecting [%v] got [%v]", twizo.ErrVerificationCooldown, err)
+	}
+	wait, err := flow.GetNextSendIn()
+	if err != nil || wait <= 0 {
+		t.Fatalf("Invalid next send expecting [> 0] got [%s] [%v]", wait, err)
+	}
+
+	// resends move on to the next type, the last type is used for the remaining resends
+	for _, expect := range []string{"id-telegram", "id-telegram"} {
+		if wait, err = flow.GetNextSendIn(); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(wait)
+		if _, err := flow.Send(); err != nil {
+			t.Fatal(err)
+		}
+		if flow.GetMessageID() != expect {
+			t.Fatalf("Invalid verification expecting [%s] got [%s]", expect, flow.GetMessageID())
+		}
+	}
+	if fmt.Sprint(submitted()) != "[sms call telegram telegram]" {
+		t.Fatalf("Invalid submitted types expecting [sms call telegram telegram] got %v", submitted())
+	}
+
+	time.Sleep(resender.MinInterval)
+	if wait, err := flow.GetNextSendIn(); err != twizo.ErrVerificationMaxSends || wait != 0 {
+		t.Fatalf("Invalid next send expecting [%v] got [%s] [%v]", twizo.ErrVerificationMaxSends, wait, err)
+	}
+	if _, err := flow.Send(); err != twizo.ErrVerificationMaxSends {
+		t.Fatalf("Invalid error expecting [%v] got [%v]", twizo.ErrVerificationMaxSends, err)
+	}
+	if flow.GetSends() != 3 {
+		t.Fatalf("Invalid amount of sends expecting [3] got [%d]", flow.GetSends())
+	}
+
+	// the limits are per recipient, shared by the flows of the resender
+	other := resender.NewVerificationFlow(request, twizo.VerificationTypeSms)
+	if _, err := other.Send(); err != twizo.ErrVerificationMaxSends {
+		t.Fatalf("Invalid error expecting [%v] got [%v]", twizo.ErrVerificationMaxSends, err)
+	}
+
+	// the token is verified against the current verification
+	httpmock.RegisterResponder(
+		http.MethodGet,
+		verificationSubmitURL("id-telegram")+"?token=12345",
+		newJSONResponder(fmt.Sprintf(
+			`{"messageId":"id-telegram","type":"telegram","statusCode":1,"_links":{"self":{"href":"%s"}}}`,
+			verificationSubmitURL("id-telegram"),
+		)),
+	)
+	outcome, err := flow.Verify("12345")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !outcome.IsSuccess() {
+		t.Fatalf("Invalid status expecting [%d] got [%d]", twizo.VerificationTokenSuccess, outcome.Status)
+	}
+}
+
+func TestVerificationFlowAllowed(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	submitted := registerVerificationSubmit(twizo.VerificationTypeCall, twizo.VerificationTypeLine)
+
+	request, err := twizo.NewVerificationRequest("31600000000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	flow := twizo.NewVerificationFlow(
+		request,
+		twizo.VerificationTypeSms,
+		twizo.VerificationTypeCall,
+		twizo.VerificationTypeLine,
+	)
+	flow.Allowed = twizo.VerificationTypes{twizo.VerificationTypeCall, twizo.VerificationTypeLine}
+
+	_, err = flow.Send()
+	if !errors.Is(err, twizo.ErrValidation) {
+		t.Fatalf("Invalid error expecting [%v] got [%v]", twizo.ErrValidation, err)
+	}
+	if fmt.Sprint(submitted()) != "[call line]" {
+		t.Fatalf("Invalid submitted types expecting [call line] got %v", submitted())
+	}
+	if flow.GetSends() != 0 || flow.GetResponse() != nil {
+		t.Fatalf("Expecting no verification got [%d] sends", flow.GetSends())
+	}
+}
+
+func TestVerificationFlowAllowedChanged(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	submitted := registerVerificationSubmit()
+
+	request, err := twizo.NewVerificationRequest("31600000000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resender := twizo.NewVerificationResender()
+	resender.MinInterval = 0
+	flow := resender.NewVerificationFlow(
+		request,
+		twizo.VerificationTypeSms,
+		twizo.VerificationTypeCall,
+		twizo.VerificationTypeTelegram,
+		twizo.VerificationTypeLine,
+	)
+	flow.Allowed = twizo.VerificationTypes{twizo.VerificationTypeSms, twizo.VerificationTypeTelegram}
+
+	// sms, the next type is telegram
+	if _, err := flow.Send(); err != nil {
+		t.Fatal(err)
+	}
+	// allowing more types after the first send continues at the next type
+	flow.Allowed = nil
+	for i := 0; i < 2; i++ {
+		if _, err := flow.Send(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// disallowing the next type moves on to the last allowed type
+	flow.Allowed = twizo.VerificationTypes{twizo.VerificationTypeSms, twizo.VerificationTypeCall}
+	if _, err := flow.Send(); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(submitted()) != "[sms telegram line call]" {
+		t.Fatalf("Invalid submitted types expecting [sms telegram line call] got %v", submitted())
+	}
+}
@@ -0,0 +1,270 @@
+package twizo
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultVerificationResendInterval is the default minimum time between two sends to a recipient
+	DefaultVerificationResendInterval = 30 * time.Second
+
+	// DefaultVerificationMaxResends is the default maximum amount of resends to a recipient
+	DefaultVerificationMaxResends = 3
+
+	// DefaultVerificationResendWindow is the default time the sends to a recipient are remembered
+	DefaultVerificationResendWindow = time.Hour
+)
+
+// VerificationResendState contains the sends to a recipient
+type VerificationResendState struct {
+	// Resends is the amount of sends after the first send
+	Resends int
+
+	// LastSend is the time of the last send
+	LastSend time.Time
+}
+
+// VerificationResendStore stores the resend state per recipient, example in a database shared by multiple
+// processes. Get and Set of a recipient are not atomic, concurrent sends to the same recipient from
+// multiple processes can exceed the limits.
+type VerificationResendStore interface {
+	// Get returns the state of recipient, nil when it is not stored or expired
+	Get(ctx context.Context, recipient Recipient) (*VerificationResendState, error)
+
+	// Set stores the state of recipient until ttl has passed, a nil state removes it
+	Set(ctx context.Context, recipient Recipient, state *VerificationResendState, ttl time.Duration) error
+}
+
+// MemoryVerificationResendStore is a VerificationResendStore in memory
+type MemoryVerificationResendStore struct {
+	mu     sync.Mutex
+	states map[Recipient]memoryVerificationResendState
+}
+
+type memoryVerificationResendState struct {
+	state   VerificationResendState
+	expires time.Time
+}
+
+// NewMemoryVerificationResendStore creates a new empty store
+func NewMemoryVerificationResendStore() *MemoryVerificationResendStore {
+	return &MemoryVerificationResendStore{states: map[Recipient]memoryVerificationResendState{}}
+}
+
+// Get returns the state of recipient, nil when it is not stored or expired
+func (s *MemoryVerificationResendStore) Get(
+	ctx context.Context,
+	recipient Recipient,
+) (*VerificationResendState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.states[recipient]
+	if !ok || !time.Now().Before(stored.expires) {
+		return nil, nil
+	}
+	state := stored.state
+	return &state, nil
+}
+
+// Set stores the state of recipient until ttl has passed, expired states are removed
+func (s *MemoryVerificationResendStore) Set(
+	ctx context.Context,
+	recipient Recipient,
+	state *VerificationResendState,
+	ttl time.Duration,
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for r, stored := range s.states {
+		if !now.Before(stored.expires) {
+			delete(s.states, r)
+		}
+	}
+	if state == nil {
+		delete(s.states, recipient)
+		return nil
+	}
+	s.states[recipient] = memoryVerificationResendState{state: *state, expires: now.Add(ttl)}
+	return nil
+}
+
+// VerificationResender sends verifications limiting the sends per recipient. Every send to a recipient
+// within Window of the last send is a resend, it is only allowed MinInterval after the last send and
+// only MaxResends times. Recipients are normalised using ParseRecipient, so different notations of the
+// same number share their limits.
+type VerificationResender struct {
+	// MinInterval is the minimum time between two sends to a recipient
+	MinInterval time.Duration
+
+	// MaxResends is the maximum amount of resends to a recipient, there is no maximum when 0
+	MaxResends int
+
+	// Window is the time the sends to a recipient are remembered after the last send
+	Window time.Duration
+
+	// Store stores the sends per recipient
+	Store VerificationResendStore
+
+	// mu makes checking and updating the state of a recipient atomic within the process
+	mu sync.Mutex
+
+	client *Client
+}
+
+// NewVerificationResender creates a new resender with the default limits storing the sends in memory
+func NewVerificationResender() *VerificationResender {
+	return DefaultClient.NewVerificationResender()
+}
+
+// NewVerificationResender creates a new resender with the default limits storing the sends in memory,
+// national numbers are parsed using the DefaultCountry of the client
+func (c *Client) NewVerificationResender() *VerificationResender {
+	return &VerificationResender{
+		MinInterval: DefaultVerificationResendInterval,
+		MaxResends:  DefaultVerificationMaxResends,
+		Window:      DefaultVerificationResendWindow,
+		Store:       NewMemoryVerificationResendStore(),
+		client:      c,
+	}
+}
+
+// Send submits request when the limits of its recipient allow it
+func (r *VerificationResender) Send(request *VerificationRequest) (*VerificationResponse, error) {
+	return r.SendContext(context.Background(), request)
+}
+
+// SendContext submits request when the limits of its recipient allow it using ctx for the calls, see
+// ResendContext for the errors
+func (r *VerificationResender) SendContext(
+	ctx context.Context,
+	request *VerificationRequest,
+) (*VerificationResponse, error) {
+	return r.send(ctx, request.GetRecipient(), request.SubmitContext)
+}
+
+// Resend resends the verification of response with the same parameters when the limits allow it
+func (r *VerificationResender) Resend(response *VerificationResponse) (*VerificationResponse, error) {
+	return r.ResendContext(context.Background(), response)
+}
+
+// ResendContext resends the verification of response with the same parameters when the limits allow it
+// using ctx for the calls. The error matches ErrVerificationMaxSends when the maximum is reached and
+// ErrVerificationCooldown, a *VerificationCooldownError, within MinInterval of the last send.
+func (r *VerificationResender) ResendContext(
+	ctx context.Context,
+	response *VerificationResponse,
+) (*VerificationResponse, error) {
+	return r.send(ctx, response.GetRecipient(), response.ResendContext)
+}
+
+// NextResendIn returns the time until the next send to recipient is allowed, 0 when it is allowed now.
+// The error is ErrVerificationMaxSends when no more sends are allowed.
+func (r *VerificationResender) NextResendIn(ctx context.Context, recipient Recipient) (time.Duration, error) {
+	key, err := r.key(recipient)
+	if err != nil {
+		return 0, err
+	}
+	state, err := r.Store.Get(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	return r.nextResendIn(state)
+}
+
+// key returns the normalised recipient the state is stored by
+func (r *VerificationResender) key(recipient Recipient) (Recipient, error) {
+	return ParseRecipient(string(recipient), clientOrDefault(r.client).getDefaultCountry())
+}
+
+func (r *VerificationResender) nextResendIn(state *VerificationResendState) (time.Duration, error) {
+	if state == nil {
+		return 0, nil
+	}
+	if r.MaxResends > 0 && state.Resends >= r.MaxResends {
+		return 0, ErrVerificationMaxSends
+	}
+	if wait := r.MinInterval - time.Since(state.LastSend); wait > 0 {
+		return wait, nil
+	}
+	return 0, nil
+}
+
+// send submits using submit when the state of recipient allows it, the send is recorded before submit is
+// called and undone when it fails
+func (r *VerificationResender) send(
+	ctx context.Context,
+	recipient Recipient,
+	submit func(ctx context.Context) (*VerificationResponse, error),
+) (*VerificationResponse, error) {
+	recipient, err := r.key(recipient)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	previous, err := r.Store.Get(ctx, recipient)
+	if err != nil {
+		r.mu.Unlock()
+		return nil, err
+	}
+	wait, err := r.nextResendIn(previous)
+	if err != nil {
+		r.mu.Unlock()
+		return nil, err
+	}
+	if wait > 0 {
+		r.mu.Unlock()
+		return nil, &VerificationCooldownError{Wait: wait}
+	}
+
+	state := &VerificationResendState{LastSend: time.Now()}
+	if previous != nil {
+		state.Resends = previous.Resends + 1
+	}
+	err = r.Store.Set(ctx, recipient, state, r.Window)
+	r.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := submit(ctx)
+	if err != nil {
+		// nothing was sent, the previous state is restored
+		if restoreErr := r.restore(recipient, state, previous); restoreErr != nil {
+			return nil, fmt.Errorf("%w (restoring the resend state failed: %v)", err, restoreErr)
+		}
+		return nil, err
+	}
+	return response, nil
+}
+
+// restore stores previous as state of recipient, unless another send was recorded after sent. A new
+// context is used, the context of the send might be the reason it failed.
+func (r *VerificationResender) restore(recipient Recipient, sent, previous *VerificationResendState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ctx := context.Background()
+	current, err := r.Store.Get(ctx, recipient)
+	if err != nil {
+		return err
+	}
+	if current == nil || current.Resends != sent.Resends || !current.LastSend.Equal(sent.LastSend) {
+		return nil
+	}
+	return r.Store.Set(ctx, recipient, previous, r.window(previous))
+}
+
+// window returns the time state should still be remembered
+func (r *VerificationResender) window(state *VerificationResendState) time.Duration {
+	if state == nil {
+		return 0
+	}
+	return r.Window - time.Since(state.LastSend)
+}
@@ -0,0 +1,306 @@
+package twizo_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	twizo "github.com/twizoapi/lib-api-go"
+	. "github.com/twizoapi/lib-api-go/testing"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+func init() {
+	twizo.APIKey = TestAPIKey
+	twizo.RegionCurrent = TestRegion
+}
+
+// registerVerificationBodies mocks the verification submit, the returned function returns the submitted
+// bodies. The message id is "id-<n>", the submit fails when fail returns true.
+func registerVerificationBodies(fail func() bool) func() []string {
+	var bodies []string
+	httpmock.RegisterResponder(
+		http.MethodPost,
+		verificationSubmitURL(""),
+		func(req *http.Request) (*http.Response, error) {
+			body, err := ioutil.ReadAll(req.Body)
+			if err != nil {
+				return nil, err
+			}
+			if fail != nil && fail() {
+				return newProblemResponder(http.StatusUnprocessableEntity, 0)(req)
+			}
+			bodies = append(bodies, string(body))
+
+			messageID := fmt.Sprintf("id-%d", len(bodies))
+			resp := httpmock.NewStringResponse(
+				http.StatusCreated,
+				fmt.Sprintf(
+					`{"messageId":"%s","recipient":"31600000000","_links":{"self":{"href":"%s"}}}`,
+					messageID,
+					verificationSubmitURL(messageID),
+				),
+			)
+			resp.Header.Set("Content-Type", "application/json")
+			return resp, nil
+		},
+	)
+	return func() []string { return bodies }
+}
+
+func TestVerificationResend(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	bodies := registerVerificationBodies(nil)
+
+	request, err := twizo.NewVerificationRequest("31600000000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	request.SetSender("Sender")
+	request.SetBodyTemplate("Your code is %token%")
+	request.SetTokenLength(8)
+	request.SetTokenType(twizo.VerificationTokenTypeAlpha)
+	request.SetLanguage("nl")
+	request.SetSessionID("session")
+	request.SetVerificationType(twizo.VerificationTypeCall)
+
+	response, err := request.Submit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// changes to the request after the submit are not resent
+	request.SetSender("Changed")
+
+	resent, err := response.Resend()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resent.GetMessageID() != "id-2" {
+		t.Fatalf("Invalid message id expecting [id-2] got [%s]", resent.GetMessageID())
+	}
+	if len(bodies()) != 2 || bodies()[0] != bodies()[1] {
+		t.Fatalf("Invalid resend expecting [%s] got %v", bodies()[0], bodies())
+	}
+
+	// a verification retrieved by message id is resent with the parameters returned by the api
+	httpmock.RegisterResponder(
+		http.MethodGet,
+		verificationSubmitURL("messageId"),
+		newJSONResponder(
+			`{"messageId":"messageId","recipient":"31600000000","sender":"Sender","bodyTemplate":"%token%",`+
+				`"language":"nl","sessionId":"session","tokenLenght":"6","tokenType":"numeric","type":"sms"}`,
+		),
+	)
+	status, err := twizo.VerificationStatus("messageId")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := status.Resend(); err != nil {
+		t.Fatal(err)
+	}
+	expect := `{"recipient":"31600000000","bodyTemplate":"%token%","language":"nl","sender":"Sender",` +
+		`"sessionId":"session","tokenLength":6,"tokenType":"numeric","type":"sms"}`
+	if bodies()[2] != expect {
+		t.Fatalf("Invalid resend expecting [%s] got [%s]", expect, bodies()[2])
+	}
+}
+
+func TestVerificationResender(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	failing := false
+	bodies := registerVerificationBodies(func() bool { return failing })
+
+	resender := twizo.NewVerificationResender()
+	resender.MinInterval = 20 * time.Millisecond
+	resender.MaxResends = 2
+
+	request, err := twizo.NewVerificationRequest("31600000000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, err := resender.Send(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// hammering the send button
+	_, err = resender.Resend(response)
+	var cooldownErr *twizo.VerificationCooldownError
+	if !errors.Is(err, twizo.ErrVerificationCooldown) || !errors.As(err, &cooldownErr) || cooldownErr.Wait <= 0 {
+		t.Fatalf("Invalid error expecting [%v] got [%v]", twizo.ErrVerificationCooldown, err)
+	}
+	if _, err := resender.Send(request); !errors.Is(err, twizo.ErrVerificationCooldown) {
+		t.Fatalf("Invalid error expecting [%v] got [%v]", twizo.ErrVerificationCooldown, err)
+	}
+
+	ctx := context.Background()
+	wait, err := resender.NextResendIn(ctx, "31600000000")
+	if err != nil || wait <= 0 || wait > resender.MinInterval {
+		t.Fatalf("Invalid next resend expecting [0 < wait <= %s] got [%s] [%v]", resender.MinInterval, wait, err)
+	}
+
+	// a failed resend is not counted
+	time.Sleep(wait)
+	failing = true
+	if _, err := resender.Resend(response); !errors.Is(err, twizo.ErrValidation) {
+		t.Fatalf("Invalid error expecting [%v] got [%v]", twizo.ErrValidation, err)
+	}
+	failing = false
+
+	for i := 0; i < 2; i++ {
+		if wait, err = resender.NextResendIn(ctx, "31600000000"); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(wait)
+		if response, err = resender.Resend(response); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(bodies()) != 3 {
+		t.Fatalf("Invalid amount of sends expecting [3] got [%d]", len(bodies()))
+	}
+
+	time.Sleep(resender.MinInterval)
+	if _, err := resender.Resend(response); err != twizo.ErrVerificationMaxSends {
+		t.Fatalf("Invalid error expecting [%v] got [%v]", twizo.ErrVerificationMaxSends, err)
+	}
+	if _, err := resender.NextResendIn(ctx, "31600000000"); err != twizo.ErrVerificationMaxSends {
+		t.Fatalf("Invalid error expecting [%v] got [%v]", twizo.ErrVerificationMaxSends, err)
+	}
+
+	// other recipients are not limited
+	if wait, err := resender.NextResendIn(ctx, "31600000001"); err != nil || wait != 0 {
+		t.Fatalf("Invalid next resend expecting [0] got [%s] [%v]", wait, err)
+	}
+}
+
+// contextVerificationResendStore fails like a database store when the context is done
+type contextVerificationResendStore struct {
+	twizo.VerificationResendStore
+	setErr error
+}
+
+func (s *contextVerificationResendStore) Get(
+	ctx context.Context,
+	recipient twizo.Recipient,
+) (*twizo.VerificationResendState, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	return s.VerificationResendStore.Get(ctx, recipient)
+}
+
+func (s *contextVerificationResendStore) Set(
+	ctx context.Context,
+	recipient twizo.Recipient,
+	state *twizo.VerificationResendState,
+	ttl time.Duration,
+) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if s.setErr != nil && state == nil {
+		return s.setErr
+	}
+	return s.VerificationResendStore.Set(ctx, recipient, state, ttl)
+}
+
+func TestVerificationResenderNormalisesRecipients(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	bodies := registerVerificationBodies(nil)
+
+	client := twizo.NewClient(TestAPIKey, TestRegion)
+	client.DefaultCountry = "NL"
+	resender := client.NewVerificationResender()
+
+	request, err := client.NewVerificationRequest("0600000000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := resender.Send(request); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	for _, recipient := range []twizo.Recipient{"0600000000", "31600000000", "+31 6 00000000"} {
+		if wait, err := resender.NextResendIn(ctx, recipient); err != nil || wait <= 0 {
+			t.Fatalf("Invalid next resend of [%s] expecting [> 0] got [%s] [%v]", recipient, wait, err)
+		}
+	}
+	request, err = client.NewVerificationRequest("31600000000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := resender.Send(request); !errors.Is(err, twizo.ErrVerificationCooldown) {
+		t.Fatalf("Invalid error expecting [%v] got [%v]", twizo.ErrVerificationCooldown, err)
+	}
+	if len(bodies()) != 1 {
+		t.Fatalf("Invalid amount of sends expecting [1] got [%d]", len(bodies()))
+	}
+}
+
+func TestVerificationResenderRestore(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// the submit fails because the context is cancelled while it is sent
+	registerVerificationBodies(func() bool {
+		cancel()
+		return true
+	})
+
+	store := &contextVerificationResendStore{VerificationResendStore: twizo.NewMemoryVerificationResendStore()}
+	resender := twizo.NewVerificationResender()
+	resender.Store = store
+
+	request, err := twizo.NewVerificationRequest("31600000000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := resender.SendContext(ctx, request); !errors.Is(err, twizo.ErrValidation) {
+		t.Fatalf("Invalid error expecting [%v] got [%v]", twizo.ErrValidation, err)
+	}
+	if wait, err := resender.NextResendIn(context.Background(), "31600000000"); err != nil || wait != 0 {
+		t.Fatalf("Invalid next resend expecting [0] got [%s] [%v]", wait, err)
+	}
+
+	// an error restoring the state is returned
+	store.setErr = errors.New("store unavailable")
+	_, err = resender.Send(request)
+	if !errors.Is(err, twizo.ErrValidation) || !strings.Contains(err.Error(), "store unavailable") {
+		t.Fatalf("Invalid error expecting [%v] and [store unavailable] got [%v]", twizo.ErrValidation, err)
+	}
+}
+
+func TestMemoryVerificationResendStore(t *testing.T) {
+	store := twizo.NewMemoryVerificationResendStore()
+	ctx := context.Background()
+
+	err := store.Set(ctx, "31600000000", &twizo.VerificationResendState{Resends: 1}, 10*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, err := store.Get(ctx, "31600000000")
+	if err != nil || state == nil || state.Resends != 1 {
+		t.Fatalf("Invalid state expecting [1] resends got [%+v] [%v]", state, err)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	if state, _ := store.Get(ctx, "31600000000"); state != nil {
+		t.Fatalf("Expecting expired state got [%+v]", state)
+	}
+}
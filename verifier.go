@@ -0,0 +1,169 @@
+package twizo
+
+import (
+	"context"
+	"sync"
+)
+
+// VerificationAttemptsUnknown is the remaining attempts of an outcome when the amount is not known, the api
+// does not report it so only verifiers wrapped by LimitVerifyAttempts know the remaining attempts
+const VerificationAttemptsUnknown = -1
+
+// VerificationOutcome is the result of verifying a token, the same for every verification method
+type VerificationOutcome struct {
+	// Status is the result of the verification, VerificationTokenUnknown when there is no result yet
+	Status VerificationStatusCode
+
+	// RemainingAttempts is the amount of tokens that can still be tried, VerificationAttemptsUnknown when
+	// not known
+	RemainingAttempts int
+}
+
+func newVerificationOutcome(status VerificationStatusCode) VerificationOutcome {
+	return VerificationOutcome{Status: status, RemainingAttempts: VerificationAttemptsUnknown}
+}
+
+// IsSuccess is a helper function to check if the token was verified
+func (outcome VerificationOutcome) IsSuccess() bool {
+	return outcome.Status == VerificationTokenSuccess
+}
+
+// IsInvalid is a helper function to check if the token was invalid
+func (outcome VerificationOutcome) IsInvalid() bool {
+	return outcome.Status == VerificationTokenInvalid
+}
+
+// IsExpired is a helper function to check if the verification expired
+func (outcome VerificationOutcome) IsExpired() bool {
+	return outcome.Status == VerificationTokenExpired
+}
+
+// IsAlreadyVerified is a helper function to check if the verification was verified before
+func (outcome VerificationOutcome) IsAlreadyVerified() bool {
+	return outcome.Status == VerificationTokenAlreadyVerified
+}
+
+// IsFailed is a helper function to check if the verification failed, example too many invalid tokens
+func (outcome VerificationOutcome) IsFailed() bool {
+	return outcome.Status == VerificationTokenFailed
+}
+
+// IsFinal returns true when trying another token is of no use, because the verification succeeded, can no
+// longer succeed or no attempts remain
+func (outcome VerificationOutcome) IsFinal() bool {
+	switch outcome.Status {
+	case VerificationTokenUnknown, VerificationTokenInvalid:
+		return outcome.RemainingAttempts == 0
+	}
+	return true
+}
+
+// Verifier verifies the token entered by a user, whatever the verification method. The error is only
+// returned when there is no outcome, example a network error or an undocumented api error.
+type Verifier interface {
+	VerifyToken(ctx context.Context, token string) (VerificationOutcome, error)
+}
+
+// VerifierFunc is a function that is a Verifier
+type VerifierFunc func(ctx context.Context, token string) (VerificationOutcome, error)
+
+// VerifyToken calls f
+func (f VerifierFunc) VerifyToken(ctx context.Context, token string) (VerificationOutcome, error) {
+	return f(ctx, token)
+}
+
+// NewVerificationVerifier returns a verifier for a verification (sms, call, telegram, ...). The outcome of
+// a biovoice verification is its status, the user speaks instead of entering a token so token is ignored.
+func NewVerificationVerifier(response *VerificationResponse) Verifier {
+	return VerifierFunc(func(ctx context.Context, token string) (VerificationOutcome, error) {
+		var err error
+		if VerificationType(response.GetVerificationType()) == VerificationTypeBioVoice {
+			err = response.StatusContext(ctx)
+		} else {
+			err = response.VerifyContext(ctx, token)
+		}
+		if err != nil {
+			return VerificationOutcome{}, err
+		}
+		return newVerificationOutcome(response.GetStatusCode()), nil
+	})
+}
+
+// NewTotpVerifier returns a verifier for the totp of the identifier of request
+func NewTotpVerifier(request *TotpRequest) Verifier {
+	return VerifierFunc(func(ctx context.Context, token string) (VerificationOutcome, error) {
+		response, err := request.VerifyContext(ctx, token)
+		if err != nil {
+			statusCode, ok := verificationStatusFromError(err)
+			if !ok {
+				return VerificationOutcome{}, err
+			}
+			return newVerificationOutcome(statusCode), nil
+		}
+		return newVerificationOutcome(verificationResponseStatus(response.GetVerificationResponse())), nil
+	})
+}
+
+// NewBackupCodeVerifier returns a verifier for the backup codes of the identifier of request
+func NewBackupCodeVerifier(request *BackupCodeRequest) Verifier {
+	return VerifierFunc(func(ctx context.Context, token string) (VerificationOutcome, error) {
+		response, err := request.VerifyContext(ctx, token)
+		if err != nil {
+			return VerificationOutcome{}, err
+		}
+		return newVerificationOutcome(verificationResponseStatus(response.GetVerificationResponse())), nil
+	})
+}
+
+// NewWidgetSessionVerifier returns a verifier for a widget session, the token is entered in the widget so
+// token is ignored
+func NewWidgetSessionVerifier(response *WidgetSessionResponse) Verifier {
+	return VerifierFunc(func(ctx context.Context, token string) (VerificationOutcome, error) {
+		if err := response.VerifyContext(ctx); err != nil {
+			return VerificationOutcome{}, err
+		}
+		return newVerificationOutcome(response.GetStatusCode()), nil
+	})
+}
+
+func verificationResponseStatus(response *VerificationResponse) VerificationStatusCode {
+	if response == nil {
+		return VerificationTokenUnknown
+	}
+	return response.GetStatusCode()
+}
+
+// LimitVerifyAttempts returns a verifier allowing maxAttempts invalid tokens, after that the outcome is
+// failed without calling verifier. The outcomes report the remaining attempts, the outcome of the last
+// allowed invalid token is invalid with 0 remaining attempts.
+func LimitVerifyAttempts(verifier Verifier, maxAttempts int) Verifier {
+	limiter := &attemptLimiter{verifier: verifier, maxAttempts: maxAttempts}
+	return VerifierFunc(limiter.verifyToken)
+}
+
+type attemptLimiter struct {
+	verifier    Verifier
+	maxAttempts int
+
+	mu      sync.Mutex
+	invalid int
+}
+
+func (l *attemptLimiter) verifyToken(ctx context.Context, token string) (VerificationOutcome, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.invalid >= l.maxAttempts {
+		return VerificationOutcome{Status: VerificationTokenFailed, RemainingAttempts: 0}, nil
+	}
+
+	outcome, err := l.verifier.VerifyToken(ctx, token)
+	if err != nil {
+		return outcome, err
+	}
+	if outcome.IsInvalid() {
+		l.invalid++
+	}
+	outcome.RemainingAttempts = l.maxAttempts - l.invalid
+	return outcome, nil
+}
@@ -0,0 +1,238 @@
+package twizo_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	twizo "github.com/twizoapi/lib-api-go"
+	. "github.com/twizoapi/lib-api-go/testing"
+
+	"gopkg.in/jarcoal/httpmock.v1"
+)
+
+func init() {
+	twizo.APIKey = TestAPIKey
+	twizo.RegionCurrent = TestRegion
+}
+
+// newJSONResponder responds with body as application/json
+func newJSONResponder(body string) httpmock.Responder {
+	return func(req *http.Request) (*http.Response, error) {
+		resp := httpmock.NewStringResponse(http.StatusOK, body)
+		resp.Header.Set("Content-Type", "application/json")
+		return resp, nil
+	}
+}
+
+func TestVerifierOutcome(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	host := twizo.GetHostForRegion(twizo.RegionCurrent)
+	verificationURL := fmt.Sprintf("https://%s/%s/verification/submit/messageId", host, twizo.ClientAPIVersion)
+	totpURL := fmt.Sprintf("https://%s/%s/totp/test?token=12345", host, twizo.ClientAPIVersion)
+	backupCodeURL := fmt.Sprintf("https://%s/%s/backupcode/test?token=12345", host, twizo.ClientAPIVersion)
+
+	response := &twizo.VerificationResponse{}
+	err := response.UnmarshalJSON([]byte(fmt.Sprintf(
+		`{"messageId":"messageId","type":"sms","_links":{"self":{"href":"%s"}}}`,
+		verificationURL,
+	)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bioVoice := &twizo.VerificationResponse{}
+	err = bioVoice.UnmarshalJSON([]byte(fmt.Sprintf(
+		`{"messageId":"messageId","type":"biovoice","_links":{"self":{"href":"%s"}}}`,
+		verificationURL,
+	)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		verifier  twizo.Verifier
+		url       string
+		responder httpmock.Responder
+		expect    twizo.VerificationStatusCode
+	}{
+		{
+			"sms success",
+			twizo.NewVerificationVerifier(response),
+			verificationURL + "?token=12345",
+			newJSONResponder(fmt.Sprintf(
+				`{"messageId":"messageId","type":"sms","statusCode":1,"_links":{"self":{"href":"%s"}}}`,
+				verificationURL,
+			)),
+			twizo.VerificationTokenSuccess,
+		},
+		{
+			"sms expired",
+			twizo.NewVerificationVerifier(response),
+			verificationURL + "?token=12345",
+			newProblemResponder(http.StatusLocked, 102),
+			twizo.VerificationTokenExpired,
+		},
+		{
+			"biovoice status",
+			twizo.NewVerificationVerifier(bioVoice),
+			verificationURL,
+			newJSONResponder(fmt.Sprintf(
+				`{"messageId":"messageId","type":"biovoice","statusCode":1,"_links":{"self":{"href":"%s"}}}`,
+				verificationURL,
+			)),
+			twizo.VerificationTokenSuccess,
+		},
+		{
+			"totp success",
+			twizo.NewTotpVerifier(twizo.NewTotpRequest("test")),
+			totpURL,
+			newJSONResponder(`{"identifier":"test","_embedded":{"verification":{"type":"totp","statusCode":1}}}`),
+			twizo.VerificationTokenSuccess,
+		},
+		{
+			"totp invalid",
+			twizo.NewTotpVerifier(twizo.NewTotpRequest("test")),
+			totpURL,
+			newProblemResponder(http.StatusUnprocessableEntity, 103),
+			twizo.VerificationTokenInvalid,
+		},
+		{
+			"backupcode already verified",
+			twizo.NewBackupCodeVerifier(twizo.NewBackupCodeRequest("test")),
+			backupCodeURL,
+			newProblemResponder(http.StatusLocked, 101),
+			twizo.VerificationTokenAlreadyVerified,
+		},
+		{
+			"backupcode unknown identifier",
+			twizo.NewBackupCodeVerifier(twizo.NewBackupCodeRequest("test")),
+			backupCodeURL,
+			newProblemResponder(http.StatusNotFound, 0),
+			twizo.VerificationTokenInvalid,
+		},
+	}
+
+	for _, test := range tests {
+		httpmock.RegisterResponder(http.MethodGet, test.url, test.responder)
+
+		outcome, err := test.verifier.VerifyToken(context.Background(), "12345")
+		if err != nil {
+			t.Fatalf("Expecting no error for [%s] got [%v]", test.name, err)
+		}
+		if outcome.Status != test.expect {
+			t.Errorf("Invalid status for [%s] expecting [%d] got [%d]", test.name, test.expect, outcome.Status)
+		}
+		if outcome.RemainingAttempts != twizo.VerificationAttemptsUnknown {
+			t.Errorf("Invalid remaining attempts for [%s] got [%d]", test.name, outcome.RemainingAttempts)
+		}
+	}
+
+	// undocumented errors are returned
+	httpmock.RegisterResponder(http.MethodGet, totpURL, newProblemResponder(http.StatusLocked, 1))
+	verifier := twizo.NewTotpVerifier(twizo.NewTotpRequest("test"))
+	if _, err := verifier.VerifyToken(context.Background(), "12345"); err == nil {
+		t.Fatal("Expecting error for undocumented error code got [nil]")
+	}
+}
+
+func TestVerifierWrappedError(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	totpURL := fmt.Sprintf(
+		"https://%s/%s/totp/test?token=12345",
+		twizo.GetHostForRegion(twizo.RegionCurrent),
+		twizo.ClientAPIVersion,
+	)
+
+	// middleware wrapping the errors of the calls
+	client := twizo.NewClient(TestAPIKey, TestRegion)
+	client.Middleware = []twizo.Middleware{
+		func(next twizo.CallHandler) twizo.CallHandler {
+			return func(info *twizo.CallInfo) error {
+				if err := next(info); err != nil {
+					return fmt.Errorf("middleware: %w", err)
+				}
+				return nil
+			}
+		},
+	}
+	verifier := twizo.NewTotpVerifier(client.NewTotpRequest("test"))
+
+	tests := []struct {
+		status    int
+		errorCode int
+		expect    twizo.VerificationStatusCode
+	}{
+		{http.StatusUnprocessableEntity, 103, twizo.VerificationTokenInvalid},
+		{http.StatusLocked, 102, twizo.VerificationTokenExpired},
+	}
+	for _, test := range tests {
+		httpmock.RegisterResponder(http.MethodGet, totpURL, newProblemResponder(test.status, test.errorCode))
+
+		outcome, err := verifier.VerifyToken(context.Background(), "12345")
+		if err != nil {
+			t.Fatalf("Expecting no error for [%d:%d] got [%v]", test.status, test.errorCode, err)
+		}
+		if outcome.Status != test.expect {
+			t.Errorf(
+				"Invalid status for [%d:%d] expecting [%d] got [%d]",
+				test.status,
+				test.errorCode,
+				test.expect,
+				outcome.Status,
+			)
+		}
+	}
+}
+
+func TestLimitVerifyAttempts(t *testing.T) {
+	calls := 0
+	verifier := twizo.LimitVerifyAttempts(
+		twizo.VerifierFunc(func(ctx context.Context, token string) (twizo.VerificationOutcome, error) {
+			calls++
+			if token == "valid" {
+				return twizo.VerificationOutcome{Status: twizo.VerificationTokenSuccess}, nil
+			}
+			return twizo.VerificationOutcome{Status: twizo.VerificationTokenInvalid}, nil
+		}),
+		2,
+	)
+
+	tests := []struct {
+		token     string
+		expect    twizo.VerificationStatusCode
+		remaining int
+		final     bool
+	}{
+		{"wrong", twizo.VerificationTokenInvalid, 1, false},
+		{"wrong", twizo.VerificationTokenInvalid, 0, true},
+		{"valid", twizo.VerificationTokenFailed, 0, true},
+	}
+
+	for i, test := range tests {
+		outcome, err := verifier.VerifyToken(context.Background(), test.token)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if outcome.Status != test.expect || outcome.RemainingAttempts != test.remaining || outcome.IsFinal() != test.final {
+			t.Fatalf(
+				"Invalid outcome of attempt [%d] expecting [%d %d %v] got [%d %d %v]",
+				i+1,
+				test.expect,
+				test.remaining,
+				test.final,
+				outcome.Status,
+				outcome.RemainingAttempts,
+				outcome.IsFinal(),
+			)
+		}
+	}
+	if calls != 2 {
+		t.Fatalf("Invalid amount of calls expecting [2] got [%d]", calls)
+	}
+}
@@ -1,6 +1,7 @@
 package twizo
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -23,6 +24,7 @@ type WidgetSessionRequest struct {
 	senderNpi            int
 	senderTon            int
 	dcs                  int
+	client               *Client
 }
 
 type jsonWidgetSessionRequest struct {
@@ -202,6 +204,11 @@ func (request *WidgetSessionRequest) SetIssuer(issuer string) {
 
 // Submit wil submit the verification request
 func (request *WidgetSessionRequest) Submit() (*WidgetSessionResponse, error) {
+	return request.SubmitContext(context.Background())
+}
+
+// SubmitContext submits the widget session request using ctx for the call
+func (request *WidgetSessionRequest) SubmitContext(ctx context.Context) (*WidgetSessionResponse, error) {
 	response := &WidgetSessionResponse{}
 	response.allowedTypes = &VerificationTypes{}
 	response.requestedTypes = &VerificationTypes{}
@@ -211,7 +218,10 @@ func (request *WidgetSessionRequest) Submit() (*WidgetSessionResponse, error) {
 		return nil, err
 	}
 
-	err = GetClient(RegionCurrent, APIKey).Call(
+	client := clientOrDefault(request.client)
+	err = client.call(
+		ctx,
+		EndpointWidgetSessionSubmit,
 		http.MethodPost,
 		apiURL,
 		request,
@@ -221,6 +231,7 @@ func (request *WidgetSessionRequest) Submit() (*WidgetSessionResponse, error) {
 	if err != nil {
 		return nil, err
 	}
+	response.client = client
 
 	return response, nil
 }
@@ -258,6 +269,7 @@ type WidgetSessionResponse struct {
 	verificationIds        []string
 	verification           *VerificationResponse
 	links                  HATEOASLinks
+	client                 *Client
 }
 
 type jsonWidgetSessionResponse struct {
@@ -428,9 +440,16 @@ func (response WidgetSessionResponse) GetBackupCodeIdentifier() string {
 
 // Status Retrieve the status of the validation
 func (response *WidgetSessionResponse) Status() error {
-	newResponse := &WidgetSessionResponse{}
+	return response.StatusContext(context.Background())
+}
 
-	err := GetClient(RegionCurrent, APIKey).Call(
+// StatusContext retrieves the status of the session using ctx for the call
+func (response *WidgetSessionResponse) StatusContext(ctx context.Context) error {
+	newResponse := &WidgetSessionResponse{client: response.client}
+
+	err := clientOrDefault(response.client).call(
+		ctx,
+		EndpointWidgetSessionStatus,
 		http.MethodGet,
 		&response.links.Self.Href,
 		nil,
@@ -448,7 +467,12 @@ func (response *WidgetSessionResponse) Status() error {
 
 // Verify verifies the token entered for a verification
 func (response *WidgetSessionResponse) Verify() error {
-	newResponse := &WidgetSessionResponse{}
+	return response.VerifyContext(context.Background())
+}
+
+// VerifyContext verifies the session using ctx for the call
+func (response *WidgetSessionResponse) VerifyContext(ctx context.Context) error {
+	newResponse := &WidgetSessionResponse{client: response.client}
 
 	// to validate we need to add a query token=<token>
 	newResponse.links = response.links.getDeepClone()
@@ -460,7 +484,9 @@ func (response *WidgetSessionResponse) Verify() error {
 	}
 	newResponse.links.Self.Href.RawQuery = q.Encode()
 
-	err := GetClient(RegionCurrent, APIKey).Call(
+	err := clientOrDefault(response.client).call(
+		ctx,
+		EndpointWidgetSessionVerify,
 		http.MethodGet,
 		&newResponse.links.Self.Href,
 		nil,
@@ -468,33 +494,20 @@ func (response *WidgetSessionResponse) Verify() error {
 		newResponse,
 	)
 
-	switch apiError := err.(type) {
-	case *APIError:
-		if apiError.Status() == http.StatusUnprocessableEntity &&
-			VerificationStatusCode(apiError.ErrorCode()) == VerificationTokenInvalid {
-			response.statusCode = VerificationTokenInvalid
-		} else if apiError.Status() == http.StatusLocked &&
-			VerificationStatusCode(apiError.ErrorCode()) == VerificationTokenExpired {
-			response.statusCode = VerificationTokenExpired
-		} else if apiError.Status() == http.StatusLocked &&
-			VerificationStatusCode(apiError.ErrorCode()) == VerificationTokenAlreadyVerified {
-			response.statusCode = VerificationTokenAlreadyVerified
-		} else if apiError.Status() == http.StatusLocked &&
-			VerificationStatusCode(apiError.ErrorCode()) == VerificationTokenFailed {
-			response.statusCode = VerificationTokenFailed
-		} else {
+	if err != nil {
+		statusCode, ok := verificationStatusFromError(err)
+		if !ok {
 			// undocumented response, error out
 			return err
 		}
+		response.statusCode = statusCode
 		return nil
 	}
 
-	if err == nil {
-		// no error use response to override ourselves
-		*response = *newResponse
-	}
+	// no error use response to override ourselves
+	*response = *newResponse
 
-	return err
+	return nil
 }
 
 // IsTokenUnknown is a helper function to check the token status is unknown
@@ -535,19 +548,34 @@ func (response WidgetSessionResponse) String() string {
 
 // NewWidgetSessionRequest creates a new widgetsession using a recipient (the only required var)
 func NewWidgetSessionRequest() *WidgetSessionRequest {
-	widgetSessionRequest := &WidgetSessionRequest{}
+	return DefaultClient.NewWidgetSessionRequest()
+}
+
+// NewWidgetSessionRequest creates a new widgetsession that will be submitted using the client
+func (c *Client) NewWidgetSessionRequest() *WidgetSessionRequest {
+	widgetSessionRequest := &WidgetSessionRequest{client: c}
 	widgetSessionRequest.allowedTypes = VerificationTypes{}
 	return widgetSessionRequest
 }
 
 // WidgetSessionStatus retrieves the status of the validation using the messageId
 func WidgetSessionStatus(sessionToken string) (*WidgetSessionResponse, error) {
+	return DefaultClient.WidgetSessionStatus(sessionToken)
+}
+
+// WidgetSessionStatus retrieves the status of the widget session using the client
+func (c *Client) WidgetSessionStatus(sessionToken string) (*WidgetSessionResponse, error) {
+	return c.WidgetSessionStatusContext(context.Background(), sessionToken)
+}
+
+// WidgetSessionStatusContext retrieves the status of the widget session using ctx for the call
+func (c *Client) WidgetSessionStatusContext(ctx context.Context, sessionToken string) (*WidgetSessionResponse, error) {
 	apiURL, err := GetURLFor(fmt.Sprintf("widget/session/%s", url.PathEscape(sessionToken)))
 	if err != nil {
 		return nil, err
 	}
 
-	request := &WidgetSessionResponse{sessionToken: sessionToken, links: createSelfLinks(apiURL)}
-	err = request.Status()
+	request := &WidgetSessionResponse{sessionToken: sessionToken, links: createSelfLinks(apiURL), client: c}
+	err = request.StatusContext(ctx)
 	return request, err
 }